@@ -0,0 +1,160 @@
+package inertia
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// PropSize is the encoded size of a prop.
+type PropSize struct {
+	Key  string
+	Size int
+}
+
+// BudgetExceededInfo is the information passed to Hooks.BudgetExceeded.
+type BudgetExceededInfo struct {
+	Request   *http.Request
+	Component string
+
+	// Props are the props exceeding Config.MaxPropSize,
+	// the largest first.
+	Props []PropSize
+
+	// PageSize is the encoded size of the page.
+	PageSize int
+
+	MaxPropSize int
+	MaxPageSize int
+}
+
+// BudgetExceededError is returned when a rendered page exceeds
+// Config.MaxPropSize or Config.MaxPageSize.
+//
+// It is returned only when running without -tags=production.
+type BudgetExceededError struct {
+	Info BudgetExceededInfo
+}
+
+func (e *BudgetExceededError) Error() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "inertia: payload budget exceeded by %s", e.Info.Component)
+
+	if e.Info.MaxPageSize > 0 && e.Info.PageSize > e.Info.MaxPageSize {
+		fmt.Fprintf(&sb, ": page is %d bytes (max %d)", e.Info.PageSize, e.Info.MaxPageSize)
+	}
+
+	if len(e.Info.Props) > 0 {
+		fmt.Fprintf(&sb, ": props over %d bytes: ", e.Info.MaxPropSize)
+
+		for i, p := range e.Info.Props {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			fmt.Fprintf(&sb, "%s (%d bytes)", p.Key, p.Size)
+		}
+	}
+
+	return sb.String()
+}
+
+// checkBudget checks the encoded sizes of the page and its props
+// against the configured budgets.
+//
+// The pageBytes are the encoded page. If nil, the page is encoded to be
+// measured. The props are encoded only if the page is at least
+// maxPropSize bytes, as no prop can be larger than the page.
+//
+// If the budget is exceeded, the BudgetExceeded hook is called and
+// the overrun is logged. In strict mode a BudgetExceededError is returned.
+func (r *Renderer) checkBudget(ctx context.Context, req *http.Request, page *Page, pageBytes []byte) error {
+	if r.maxPropSize <= 0 && r.maxPageSize <= 0 {
+		return nil
+	}
+
+	if pageBytes == nil {
+		b, release, err := encodePage(r.encoder, page)
+		if err != nil {
+			return fmt.Errorf("inertia: failed to encode page: %w", err)
+		}
+		defer release()
+
+		pageBytes = b
+	}
+
+	//nolint:exhaustruct
+	info := BudgetExceededInfo{
+		Request:     req,
+		Component:   page.Component,
+		PageSize:    len(pageBytes),
+		MaxPropSize: r.maxPropSize,
+		MaxPageSize: r.maxPageSize,
+	}
+
+	if r.maxPropSize > 0 && info.PageSize >= r.maxPropSize {
+		for key, value := range page.Props {
+			size, err := r.encodedSize(value)
+			if err != nil {
+				return fmt.Errorf("inertia: failed to encode prop %s: %w", key, err)
+			}
+
+			if size > r.maxPropSize {
+				info.Props = append(info.Props, PropSize{Key: key, Size: size})
+			}
+		}
+
+		slices.SortFunc(info.Props, func(a, b PropSize) int {
+			return b.Size - a.Size
+		})
+	}
+
+	if len(info.Props) == 0 && (r.maxPageSize <= 0 || info.PageSize <= r.maxPageSize) {
+		return nil
+	}
+
+	budgetErr := &BudgetExceededError{Info: info}
+
+	r.hooks.budgetExceeded(ctx, info)
+
+	if r.strictBudget {
+		return budgetErr
+	}
+
+	if r.logger.Enabled(ctx, slog.LevelWarn) {
+		props := make([]any, 0, len(info.Props))
+		for _, p := range info.Props {
+			props = append(props, slog.Int(p.Key, p.Size))
+		}
+
+		r.logger.LogAttrs(ctx, slog.LevelWarn, "inertia: payload budget exceeded",
+			slog.String("component", info.Component),
+			slog.Int("page_size", info.PageSize),
+			slog.Int("max_page_size", info.MaxPageSize),
+			slog.Int("max_prop_size", info.MaxPropSize),
+			slog.Group("props", props...),
+		)
+	}
+
+	return nil
+}
+
+// encodedSize returns the size of v encoded with the renderer encoder.
+func (r *Renderer) encodedSize(v any) (int, error) {
+	buf := bufPool.Get().(*bytes.Buffer) //nolint:forcetypeassert
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+
+	if err := r.encoder.Encode(buf, v); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return len(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
@@ -0,0 +1,180 @@
+package inertia
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestRenderer_PayloadBudget(t *testing.T) {
+	t.Parallel()
+
+	props := Props{
+		NewProp("users", strings.Repeat("u", 100), nil),
+		NewProp("posts", strings.Repeat("p", 50), nil),
+		NewProp("title", "Users", nil),
+	}
+
+	tests := []struct {
+		name          string
+		config        Config
+		expectedProps []PropSize
+		exceeded      bool
+	}{
+		{
+			name:   "no budget",
+			config: Config{},
+		},
+		{
+			name:   "within budget",
+			config: Config{MaxPropSize: 1024, MaxPageSize: 4096},
+		},
+		{
+			name:     "prop over budget",
+			config:   Config{MaxPropSize: 40},
+			exceeded: true,
+			expectedProps: []PropSize{
+				{Key: "users", Size: 102},
+				{Key: "posts", Size: 52},
+			},
+		},
+		{
+			name:     "page over budget",
+			config:   Config{MaxPageSize: 100},
+			exceeded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("strict", func(t *testing.T) {
+				t.Parallel()
+
+				var reported []BudgetExceededInfo
+
+				config := tt.config
+				config.Hooks = &Hooks{BudgetExceeded: func(_ context.Context, info BudgetExceededInfo) {
+					reported = append(reported, info)
+				}}
+
+				renderer := New(testTpl, &config)
+				renderer.strictBudget = true
+
+				req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+				err := renderer.Render(w, req, "Users", NewRenderContext(WithProps(props)))
+
+				if !tt.exceeded {
+					require.NoError(t, err)
+					assert.Empty(t, reported)
+
+					return
+				}
+
+				var budgetErr *BudgetExceededError
+				require.ErrorAs(t, err, &budgetErr)
+				assert.Equal(t, "Users", budgetErr.Info.Component)
+				assert.Equal(t, tt.expectedProps, budgetErr.Info.Props)
+				assert.Len(t, reported, 1)
+			})
+
+			t.Run("non-strict", func(t *testing.T) {
+				t.Parallel()
+
+				var (
+					reported []BudgetExceededInfo
+					buf      bytes.Buffer
+				)
+
+				config := tt.config
+				config.Logger = newTestLogger(&buf)
+				config.Hooks = &Hooks{BudgetExceeded: func(_ context.Context, info BudgetExceededInfo) {
+					reported = append(reported, info)
+				}}
+
+				renderer := New(testTpl, &config)
+				renderer.strictBudget = false
+
+				req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+				require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+				if !tt.exceeded {
+					assert.Empty(t, reported)
+					assert.NotContains(t, buf.String(), "payload budget exceeded")
+
+					return
+				}
+
+				require.Len(t, reported, 1)
+				assert.Equal(t, tt.expectedProps, reported[0].Props)
+				assert.Contains(t, buf.String(), "inertia: payload budget exceeded")
+
+				if tt.config.MaxPageSize > 0 {
+					assert.Greater(t, reported[0].PageSize, tt.config.MaxPageSize)
+				}
+			})
+		})
+	}
+}
+
+func TestRenderer_PayloadBudgetEncodesOnce(t *testing.T) {
+	t.Parallel()
+
+	for name, reqConfig := range map[string]*inertiatest.RequestConfig{
+		"inertia": {Inertia: true},
+		"html":    nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+
+			renderer := New(testTpl, &Config{
+				MaxPropSize: 1024,
+				MaxPageSize: 4096,
+				JSONEncoder: JSONEncoderFunc(func(w io.Writer, v any) error {
+					calls.Add(1)
+					return DefaultJSONEncoder.Encode(w, v)
+				}),
+			})
+
+			req, w := inertiatest.NewRequest(http.MethodGet, "/", reqConfig)
+			require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(
+				WithProps(NewProp("title", "Users", nil)),
+			)))
+
+			assert.Equal(t, int32(1), calls.Load(), "the page must be encoded once")
+		})
+	}
+}
+
+func TestBudgetExceededError(t *testing.T) {
+	t.Parallel()
+
+	err := &BudgetExceededError{Info: BudgetExceededInfo{
+		Component:   "Users",
+		PageSize:    5000,
+		MaxPageSize: 4096,
+		MaxPropSize: 1024,
+		Props: []PropSize{
+			{Key: "users", Size: 4000},
+			{Key: "posts", Size: 2000},
+		},
+	}}
+
+	assert.Equal(t,
+		"inertia: payload budget exceeded by Users: page is 5000 bytes (max 4096): "+
+			"props over 1024 bytes: users (4000 bytes), posts (2000 bytes)",
+		err.Error(),
+	)
+}
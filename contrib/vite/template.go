@@ -8,32 +8,49 @@ import (
 	"net/url"
 
 	"go.inout.gg/foundations/must"
+
+	"go.inout.gg/inertia"
 )
 
 // parseTemplate parses a template from a string.
 func parseTemplate(name string, content string) *template.Template {
-	return template.Must(template.New(name).Parse(content))
+	return template.Must(template.New(name).Funcs(template.FuncMap{"viteNonce": nonceOf}).Parse(content))
 }
 
+// nonceAttr is a template snippet that renders the nonce attribute
+// if the template is executed with data carrying a non-empty nonce,
+// see nonceOf.
+const nonceAttr = `{{with viteNonce .}} nonce="{{.}}"{{end}}`
+
 // newTemplate creates a new template with Vite support.
 func newTemplate(cfg *Config) *template.Template {
 	viteClientURL := must.Must(url.JoinPath(cfg.ViteAddress, "@vite/client"))
 	viteReactRefreshURL := must.Must(url.JoinPath(cfg.ViteAddress, "@react-refresh"))
-	viteClientTemplate := fmt.Sprintf(`<script type="module" src="%s"></script>`, viteClientURL)
-	viteReactRefreshTemplate := fmt.Sprintf(`<script type="module">
+	viteClientTemplate := fmt.Sprintf(`<script type="module"%s src="%s"></script>`, nonceAttr, viteClientURL)
+	viteReactRefreshTemplate := fmt.Sprintf(`<script type="module"%s>
   import RefreshRuntime from "%s";
   RefreshRuntime.injectIntoGlobalHook(window);
   window.$RefreshReg$ = () => {};
   window.$RefreshSig$ = () => (type) => type;
   window.__vite_plugin_react_preamble_installed__ = true;
-</script>`, viteReactRefreshURL)
+</script>`, nonceAttr, viteReactRefreshURL)
 
 	tpl := template.New(cfg.TemplateName).Funcs(template.FuncMap{
-		"viteResource": func(path string) template.HTML {
+		"viteNonce": nonceOf,
+		"viteResource": func(path string, data ...any) template.HTML {
 			url := must.Must(url.JoinPath(cfg.ViteAddress, path))
 
+			var nonce string
+			if len(data) > 0 {
+				nonce = nonceOf(data[0])
+			}
+
 			//nolint:gosec
-			return template.HTML(fmt.Sprintf(`<script type="module" src="%s"></script>`, url))
+			return template.HTML(fmt.Sprintf(
+				`<script type="module"%s src="%s"></script>`,
+				nonceAttrValue(nonce),
+				url,
+			))
 		},
 	})
 
@@ -47,3 +64,32 @@ func newTemplate(cfg *Config) *template.Template {
 
 	return tpl
 }
+
+// nonceOf returns the Content-Security-Policy nonce carried by the
+// template data: the *inertia.TemplateData, a value with a Nonce method
+// or the nonce itself. Other values carry no nonce.
+func nonceOf(data any) string {
+	switch v := data.(type) {
+	case *inertia.TemplateData:
+		if v != nil {
+			return v.Nonce
+		}
+	case inertia.TemplateData:
+		return v.Nonce
+	case interface{ Nonce() string }:
+		return v.Nonce()
+	case string:
+		return v
+	}
+
+	return ""
+}
+
+// nonceAttrValue renders the nonce attribute, if any.
+func nonceAttrValue(nonce string) string {
+	if nonce == "" {
+		return ""
+	}
+
+	return ` nonce="` + template.HTMLEscapeString(nonce) + `"`
+}
@@ -2,14 +2,17 @@
 
 package vite
 
-import "html/template"
+import (
+	"html/template"
+)
 
 var noopTemplate = template.Must(template.New("noop").Parse(""))
 
 func newTemplate(c *Config) *template.Template {
 	t := template.New(c.TemplateName)
 	t.Funcs(template.FuncMap{
-		"viteResource": func(path string) template.HTML {
+		"viteNonce": func(data any) string { return "" },
+		"viteResource": func(path string, data ...any) template.HTML {
 			return template.HTML("")
 		},
 	})
@@ -0,0 +1,100 @@
+//go:build !production
+
+package vite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia"
+)
+
+func TestNewTemplate_Nonce(t *testing.T) {
+	t.Parallel()
+
+	content := `{{template "viteClient" .}}{{template "viteReactRefresh" .}}{{viteResource "src/main.tsx" .}}`
+
+	t.Run("with nonce", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(content, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, &inertia.TemplateData{Nonce: "abc123"}))
+
+		out := b.String()
+		assert.Contains(t, out, `<script type="module" nonce="abc123" src="http://localhost:5173/@vite/client"></script>`)
+		assert.Contains(t, out, `<script type="module" nonce="abc123">`)
+		assert.Contains(t, out, `<script type="module" nonce="abc123" src="http://localhost:5173/src/main.tsx"></script>`)
+	})
+
+	t.Run("without nonce", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(content, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, &inertia.TemplateData{}))
+
+		assert.NotContains(t, b.String(), "nonce")
+	})
+
+	t.Run("explicit nonce", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(`{{viteResource "src/main.tsx" .Nonce}}`, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, &inertia.TemplateData{Nonce: "abc123"}))
+
+		assert.Equal(t, `<script type="module" nonce="abc123" src="http://localhost:5173/src/main.tsx"></script>`, b.String())
+	})
+
+	t.Run("nonce method", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(content, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, nonceData("abc123")))
+
+		assert.Equal(t, 3, strings.Count(b.String(), `nonce="abc123"`))
+	})
+
+	t.Run("other data", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(content, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, struct{ Title string }{Title: "Home"}))
+
+		assert.Contains(t, b.String(), `<script type="module" src="http://localhost:5173/@vite/client"></script>`)
+		assert.NotContains(t, b.String(), "nonce")
+	})
+
+	t.Run("without data", func(t *testing.T) {
+		t.Parallel()
+
+		tpl, err := NewTemplate(`{{template "viteClient"}}{{viteResource "src/main.tsx"}}`, nil)
+		require.NoError(t, err)
+
+		var b strings.Builder
+		require.NoError(t, tpl.Execute(&b, nil))
+
+		assert.Equal(t, `<script type="module" src="http://localhost:5173/@vite/client"></script>`+
+			`<script type="module" src="http://localhost:5173/src/main.tsx"></script>`, b.String())
+	})
+}
+
+type nonceData string
+
+func (n nonceData) Nonce() string { return string(n) }
@@ -32,6 +32,16 @@ func (c *Config) defaults() {
 // To include Vite React Refresh, use {{template "viteReactRefresh"}}
 // and Vite client, use {{template "viteClient"}}.
 // To include a Vite resource, use {{viteResource "path/to/resource.js"}}.
+//
+// If a Content-Security-Policy nonce is used (see inertia.NonceMiddleware),
+// pass the template data to the templates and the resources, e.g.,
+// {{template "viteClient" .}} and {{viteResource "main.tsx" .}}, to add
+// the nonce attribute to the emitted elements. The nonce is read from
+// *inertia.TemplateData or a value with a Nonce() string method, and
+// other data is ignored. Templates can't see the data unless it is
+// passed, so {{template "viteClient"}} emits no nonce attribute.
+// The nonce can be passed explicitly as well, e.g.,
+// {{viteResource "main.tsx" .Nonce}}, and is available as {{viteNonce .}}.
 // When running with -tags=production, "viteClient" and "viteReactRefresh"
 // templates are blank.
 func NewTemplate(content string, config *Config) (*template.Template, error) {
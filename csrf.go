@@ -0,0 +1,296 @@
+package inertia
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.inout.gg/foundations/debug"
+	"go.inout.gg/foundations/http/httperror"
+	"go.inout.gg/foundations/http/httpmiddleware"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+const (
+	// DefaultCSRFCookieName is the default name of the cookie carrying
+	// the CSRF token. The Inertia.js client reads it and sends the token
+	// back in the X-XSRF-TOKEN header.
+	DefaultCSRFCookieName = "XSRF-TOKEN"
+
+	// DefaultCSRFHeaderName is the default name of the request header
+	// carrying the CSRF token.
+	DefaultCSRFHeaderName = inertiaheader.HeaderXXSRFToken
+
+	// DefaultPageExpiredComponent is the default component rendered by
+	// the CSRFMiddleware on a CSRF token mismatch.
+	DefaultPageExpiredComponent = "PageExpired"
+
+	// StatusPageExpired is the status code of responses to requests with
+	// a missing or invalid CSRF token.
+	StatusPageExpired = 419
+)
+
+// csrfTokenSize is the number of random bytes of a CSRF token.
+const csrfTokenSize = 32
+
+// ErrCSRFTokenMismatch is passed to CSRFConfig.ErrorHandler when a request
+// has a missing or invalid CSRF token.
+//
+// It implements the StatusCoder interface with the StatusPageExpired code.
+//
+//nolint:gochecknoglobals
+var ErrCSRFTokenMismatch error = &csrfError{}
+
+type csrfError struct{}
+
+func (*csrfError) Error() string   { return "inertia: CSRF token mismatch" }
+func (*csrfError) StatusCode() int { return StatusPageExpired }
+
+type csrfCtxKey struct{}
+
+//nolint:gochecknoglobals
+var kCSRFCtxKey = csrfCtxKey{}
+
+// CSRFConfig is the configuration for CSRFMiddleware.
+type CSRFConfig struct {
+	// ErrorHandler handles requests with a missing or invalid CSRF token.
+	// It receives ErrCSRFTokenMismatch.
+	//
+	// It defaults to a handler rendering PageExpiredComponent with
+	// the "status" prop and the StatusPageExpired status code.
+	// An ErrorPageHandler can be used as well.
+	ErrorHandler httperror.ErrorHandler
+
+	// Logger is used to log rejected requests.
+	//
+	// It defaults to the logger of the Renderer.
+	Logger *slog.Logger
+
+	// CookieName is the name of the cookie carrying the token.
+	//
+	// It defaults to DefaultCSRFCookieName.
+	CookieName string
+
+	// HeaderName is the name of the request header carrying the token.
+	//
+	// It defaults to DefaultCSRFHeaderName.
+	HeaderName string
+
+	// CookiePath is the path of the cookie.
+	//
+	// It defaults to "/".
+	CookiePath string
+
+	// CookieDomain is the domain of the cookie.
+	//
+	// It is optional.
+	CookieDomain string
+
+	// PageExpiredComponent is the component rendered by the default
+	// ErrorHandler.
+	//
+	// It defaults to DefaultPageExpiredComponent.
+	PageExpiredComponent string
+
+	// SameSite is the SameSite attribute of the cookie.
+	//
+	// It defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// SessionID returns an identifier of the user session of the request,
+	// e.g., the ID of the session cookie, or an empty string if there is
+	// no session. Tokens are bound to it, so a token planted by an attacker,
+	// e.g., from a sibling subdomain, is rejected for another session.
+	// A token is renewed when the session changes, e.g., on login.
+	//
+	// It is optional, but strongly recommended. Without it, tokens are only
+	// bound to the key and the protection is that of a plain double-submit
+	// cookie: CookieName must then use the "__Host-" prefix, with
+	// CookiePath "/", no CookieDomain and Secure, so the cookie cannot be
+	// set by other hosts.
+	SessionID func(r *http.Request) string
+
+	// Secure sets the Secure attribute of the cookie, e.g., when served
+	// over HTTPS behind a reverse proxy. It is always set for requests
+	// received over TLS.
+	Secure bool
+}
+
+func (c *CSRFConfig) defaults(renderer *Renderer) {
+	c.Logger = cmp.Or(c.Logger, renderer.Logger())
+	c.CookieName = cmp.Or(c.CookieName, DefaultCSRFCookieName)
+	c.HeaderName = cmp.Or(c.HeaderName, DefaultCSRFHeaderName)
+	c.CookiePath = cmp.Or(c.CookiePath, "/")
+	c.PageExpiredComponent = cmp.Or(c.PageExpiredComponent, DefaultPageExpiredComponent)
+	c.SameSite = cmp.Or(c.SameSite, http.SameSiteLaxMode)
+
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = pageExpiredHandler(renderer, c.PageExpiredComponent)
+	}
+
+	if c.SessionID == nil {
+		c.SessionID = func(*http.Request) string { return "" }
+	}
+}
+
+// CSRFMiddleware protects unsafe requests against cross-site request forgery
+// using the XSRF-TOKEN flow supported by the Inertia.js client.
+//
+// It issues a token signed with key and bound to CSRFConfig.SessionID in
+// a cookie readable by JavaScript.
+// The Inertia.js client sends the token back in the X-XSRF-TOKEN header,
+// and requests with methods other than GET, HEAD, OPTIONS and TRACE must
+// carry a header matching the cookie. Otherwise, the request is passed to
+// CSRFConfig.ErrorHandler with ErrCSRFTokenMismatch, and the response
+// carries a fresh token for the next attempt.
+//
+// The token is available with CSRFTokenFromContext, e.g., to embed it in
+// the root template.
+//
+// The key must be secret and shared by all instances of the application.
+// It can be used with inertiaframe through MountOpts.Middleware.
+func CSRFMiddleware(renderer *Renderer, key []byte, opts ...func(*CSRFConfig)) httpmiddleware.MiddlewareFunc {
+	debug.Assert(len(key) > 0, "expected key to be defined")
+
+	//nolint:exhaustruct
+	config := CSRFConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	config.defaults(renderer)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sessionID := config.SessionID(r)
+
+			token := ""
+			if c, err := r.Cookie(config.CookieName); err == nil && verifyCSRFToken(key, sessionID, c.Value) {
+				token = c.Value
+			}
+
+			valid := token != ""
+			if !valid {
+				token = newCSRFToken(key, sessionID)
+
+				//nolint:exhaustruct
+				http.SetCookie(w, &http.Cookie{
+					Name:     config.CookieName,
+					Value:    token,
+					Path:     config.CookiePath,
+					Domain:   config.CookieDomain,
+					SameSite: config.SameSite,
+					Secure:   config.Secure || r.TLS != nil,
+					HttpOnly: false, // read by the Inertia.js client
+				})
+			}
+
+			r = r.WithContext(ContextWithCSRFToken(r.Context(), token))
+
+			if isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get(config.HeaderName)
+			if !valid || subtle.ConstantTimeCompare([]byte(header), []byte(token)) != 1 {
+				d("CSRF token mismatch for %s %s", r.Method, r.URL.Path)
+
+				config.Logger.LogAttrs(r.Context(), slog.LevelInfo, "inertia: CSRF token mismatch",
+					slog.String("method", r.Method),
+					slog.String("url", RequestURL(r)),
+					slog.Bool("cookie", valid),
+					slog.Bool("header", header != ""),
+				)
+
+				config.ErrorHandler.ServeHTTP(w, r, ErrCSRFTokenMismatch)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContextWithCSRFToken returns a copy of ctx carrying the CSRF token.
+func ContextWithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, kCSRFCtxKey, token)
+}
+
+// CSRFTokenFromContext returns the CSRF token stored in ctx by the
+// CSRFMiddleware, or an empty string if there is none.
+func CSRFTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(kCSRFCtxKey).(string)
+	return token
+}
+
+// pageExpiredHandler returns an error handler rendering the component with
+// the StatusPageExpired status code.
+func pageExpiredHandler(renderer *Renderer, component string) httperror.ErrorHandler {
+	return httperror.ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request, _ error) {
+		err := renderer.Render(w, r, component, NewRenderContext(
+			WithStatus(StatusPageExpired),
+			WithProps(NewAlways("status", StatusPageExpired)),
+		))
+		if err != nil {
+			d("Failed to render page expired component: %v", err)
+			http.Error(w, "Page Expired", StatusPageExpired)
+		}
+	})
+}
+
+// newCSRFToken generates a new random token bound to the session ID and
+// signed with key.
+func newCSRFToken(key []byte, sessionID string) string {
+	b := make([]byte, csrfTokenSize)
+	_, _ = rand.Read(b) // never returns an error
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token + "." + signCSRFToken(key, sessionID, token)
+}
+
+// verifyCSRFToken reports whether value is a token bound to the session ID
+// and signed with key.
+func verifyCSRFToken(key []byte, sessionID, value string) bool {
+	token, sig, ok := strings.Cut(value, ".")
+	if !ok || token == "" {
+		return false
+	}
+
+	return hmac.Equal([]byte(sig), []byte(signCSRFToken(key, sessionID, token)))
+}
+
+// signCSRFToken returns the HMAC of the session ID and the token.
+// The session ID is length-prefixed, so the message is unambiguous.
+func signCSRFToken(key []byte, sessionID, token string) string {
+	mac := hmac.New(sha256.New, key)
+
+	// hash.Hash never returns an error.
+	_, _ = mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(sessionID))))
+	_, _ = mac.Write([]byte(sessionID))
+	_, _ = mac.Write([]byte(token))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isSafeMethod reports whether the method is safe as defined
+// by RFC 9110, Section 9.2.1.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
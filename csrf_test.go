@@ -0,0 +1,231 @@
+package inertia
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/foundations/http/httperror"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	renderer := New(testTpl, &Config{Version: "1"})
+
+	newHandler := func(opts ...func(*CSRFConfig)) http.Handler {
+		return CSRFMiddleware(renderer, key, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(CSRFTokenFromContext(r.Context())))
+		}))
+	}
+
+	validToken := newCSRFToken(key, "")
+
+	t.Run("issues token on safe request", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		newHandler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, DefaultCSRFCookieName, cookies[0].Name)
+		assert.False(t, cookies[0].HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+		assert.True(t, verifyCSRFToken(key, "", cookies[0].Value))
+		assert.Equal(t, cookies[0].Value, w.Body.String())
+	})
+
+	t.Run("keeps valid token", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: validToken})
+
+		newHandler().ServeHTTP(w, req)
+
+		assert.Empty(t, w.Result().Cookies())
+		assert.Equal(t, validToken, w.Body.String())
+	})
+
+	t.Run("accepts matching header", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodPost, "/", &inertiatest.RequestConfig{Inertia: true, Version: "1"})
+		req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: validToken})
+		req.Header.Set(inertiaheader.HeaderXXSRFToken, validToken)
+
+		newHandler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, validToken, w.Body.String())
+	})
+
+	forged := newCSRFToken([]byte("another key"), "")
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+	}{
+		{name: "missing cookie and header"},
+		{name: "missing header", cookie: validToken},
+		{name: "missing cookie", header: validToken},
+		{name: "mismatch", cookie: validToken, header: newCSRFToken(key, "")},
+		{name: "forged signature", cookie: forged, header: forged},
+		{name: "malformed", cookie: "token", header: "token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, w := inertiatest.NewRequest(http.MethodPost, "/", &inertiatest.RequestConfig{Inertia: true, Version: "1"})
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: tt.cookie})
+			}
+
+			if tt.header != "" {
+				req.Header.Set(inertiaheader.HeaderXXSRFToken, tt.header)
+			}
+
+			newHandler().ServeHTTP(w, req)
+
+			assert.Equal(t, StatusPageExpired, w.Code)
+			assert.Equal(t, "true", w.Header().Get(inertiaheader.HeaderXInertia))
+
+			var p Page
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			assert.Equal(t, DefaultPageExpiredComponent, p.Component)
+			assert.Equal(t, float64(StatusPageExpired), p.Props["status"])
+		})
+	}
+
+	t.Run("renews invalid cookie on mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodPost, "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: forged})
+		req.Header.Set(inertiaheader.HeaderXXSRFToken, forged)
+
+		newHandler().ServeHTTP(w, req)
+
+		assert.Equal(t, StatusPageExpired, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.NotEqual(t, forged, cookies[0].Value)
+		assert.True(t, verifyCSRFToken(key, "", cookies[0].Value))
+	})
+
+	t.Run("custom config", func(t *testing.T) {
+		t.Parallel()
+
+		var handledErr error
+
+		h := newHandler(func(c *CSRFConfig) {
+			c.CookieName = "csrf"
+			c.HeaderName = "X-CSRF-Token"
+			c.Secure = true
+			c.ErrorHandler = httperror.ErrorHandlerFunc(func(w http.ResponseWriter, _ *http.Request, err error) {
+				handledErr = err
+				w.WriteHeader(http.StatusForbidden)
+			})
+		})
+
+		req, w := inertiatest.NewRequest(http.MethodDelete, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "csrf", Value: validToken})
+		req.Header.Set(inertiaheader.HeaderXXSRFToken, validToken)
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		require.ErrorIs(t, handledErr, ErrCSRFTokenMismatch)
+
+		req, w = inertiatest.NewRequest(http.MethodDelete, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "csrf", Value: validToken})
+		req.Header.Set("X-CSRF-Token", validToken)
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("session binding", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(func(c *CSRFConfig) {
+			c.SessionID = func(r *http.Request) string {
+				if c, err := r.Cookie("session_id"); err == nil {
+					return c.Value
+				}
+
+				return ""
+			}
+		})
+
+		// A token issued for the attacker session.
+		planted := newCSRFToken(key, "attacker")
+
+		tests := []struct {
+			name    string
+			session string
+			status  int
+		}{
+			{name: "same session", session: "attacker", status: http.StatusOK},
+			{name: "other session", session: "victim", status: StatusPageExpired},
+			{name: "no session", status: StatusPageExpired},
+		}
+
+		for _, tt := range tests {
+			req, w := inertiatest.NewRequest(http.MethodPost, "/", &inertiatest.RequestConfig{Inertia: true, Version: "1"})
+			req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: planted})
+			req.Header.Set(inertiaheader.HeaderXXSRFToken, planted)
+
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: "session_id", Value: tt.session})
+			}
+
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, tt.name)
+		}
+
+		// The token is renewed when the session changes, e.g., on login.
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultCSRFCookieName, Value: planted})
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: "victim"})
+
+		h.ServeHTTP(w, req)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.True(t, verifyCSRFToken(key, "victim", cookies[0].Value))
+	})
+
+	t.Run("error page handler", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(func(c *CSRFConfig) { c.ErrorHandler = newErrorPage(renderer, false) })
+
+		req, w := inertiatest.NewRequest(http.MethodPost, "/", &inertiatest.RequestConfig{Inertia: true, Version: "1"})
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, StatusPageExpired, w.Code)
+
+		var p Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, DefaultErrorComponent, p.Component)
+	})
+}
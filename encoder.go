@@ -0,0 +1,66 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+var _ JSONEncoder = (JSONEncoderFunc)(nil)
+
+// DefaultJSONEncoder is the default JSONEncoder backed by encoding/json.
+//
+// It escapes HTML characters in strings.
+//
+//nolint:gochecknoglobals
+var DefaultJSONEncoder JSONEncoder = JSONEncoderFunc(func(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v) //nolint:wrapcheck
+})
+
+type (
+	// JSONEncoder encodes values, e.g., pages, into JSON.
+	JSONEncoder interface {
+		// Encode writes the JSON encoding of v to w.
+		//
+		// A trailing newline, if any, is allowed.
+		Encode(w io.Writer, v any) error
+	}
+
+	// The JSONEncoderFunc type is an adapter to allow the use of ordinary
+	// functions where JSONEncoder is expected.
+	// If f is a function with the appropriate signature, JSONEncoderFunc(f)
+	// is a [JSONEncoder] that calls f.
+	JSONEncoderFunc func(w io.Writer, v any) error
+)
+
+// Encode calls `fn(w, v)`.
+func (fn JSONEncoderFunc) Encode(w io.Writer, v any) error { return fn(w, v) }
+
+// encodePage encodes page using enc into a pooled buffer.
+//
+// The returned release function must be called once the buffer is
+// no longer used.
+func encodePage(enc JSONEncoder, page *Page) ([]byte, func(), error) {
+	buf := bufPool.Get().(*bytes.Buffer) //nolint:forcetypeassert
+	release := func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}
+
+	if err := writePage(enc, buf, page); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), release, nil
+}
+
+// writePage encodes page using enc into w.
+func writePage(enc JSONEncoder, w io.Writer, page *Page) error {
+	v, err := page.encodable()
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(w, v) //nolint:wrapcheck
+}
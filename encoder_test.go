@@ -0,0 +1,96 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+// countingEncoder returns a JSONEncoder that doesn't escape HTML and
+// counts the number of calls.
+func countingEncoder(calls *atomic.Int32) JSONEncoder {
+	return JSONEncoderFunc(func(w io.Writer, v any) error {
+		calls.Add(1)
+
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+
+		return enc.Encode(v)
+	})
+}
+
+func TestRenderer_JSONEncoder(t *testing.T) {
+	t.Parallel()
+
+	props := WithProps(NewProp("html", "<b>bold</b>", nil))
+
+	t.Run("json response", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		renderer := New(testTpl, &Config{JSONEncoder: countingEncoder(&calls)})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(props)))
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Contains(t, w.Body.String(), `"html":"<b>bold</b>"`)
+	})
+
+	t.Run("html response", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		renderer := New(testTpl, &Config{JSONEncoder: countingEncoder(&calls)})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(props)))
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Contains(t, w.Body.String(), template.HTMLEscapeString(`"html":"<b>bold</b>"}`))
+	})
+
+	t.Run("ssr payload", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls atomic.Int32
+			body  []byte
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			body = b
+
+			assert.NoError(t, json.NewEncoder(w).Encode(&SsrTemplateData{Head: "", Body: "<div></div>"}))
+		}))
+		defer server.Close()
+
+		renderer := New(testTpl, &Config{
+			JSONEncoder: countingEncoder(&calls),
+			SsrClient:   NewHTTPSsrClient(server.URL, nil),
+		})
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		var buf bytes.Buffer
+
+		require.NoError(t, renderer.RenderHTML(t.Context(), &buf, req, "Home", NewRenderContext(props)))
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Contains(t, string(body), `"html":"<b>bold</b>"`)
+	})
+}
@@ -0,0 +1,7 @@
+//go:build !production
+
+package inertia
+
+// development reports whether the package is built for development,
+// i.e., without -tags=production.
+const development = true
@@ -0,0 +1,349 @@
+package inertia
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"maps"
+	"net/http"
+	rdebug "runtime/debug"
+	"slices"
+
+	"go.inout.gg/foundations/http/httperror"
+	"go.inout.gg/foundations/http/httpmiddleware"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+// DefaultErrorComponent is the default component rendered by the
+// ErrorPageHandler in production.
+const DefaultErrorComponent = "Error"
+
+var (
+	_ httperror.ErrorHandler                    = (*errorPage)(nil)
+	_ http.ResponseWriter                       = (*recoverWriter)(nil)
+	_ interface{ Unwrap() http.ResponseWriter } = (*recoverWriter)(nil)
+)
+
+// redactedHeaders are the request headers not shown on the
+// development error page.
+//
+//nolint:gochecknoglobals
+var redactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// ErrorPageConfig is the configuration for ErrorPageHandler and
+// RecoverMiddleware.
+type ErrorPageConfig struct {
+	// Logger is used to log the handled errors.
+	//
+	// It defaults to the logger of the Renderer.
+	Logger *slog.Logger
+
+	// Errors maps errors and status codes to components, e.g.,
+	// 404 Not Found to "Errors/NotFound".
+	//
+	// It is optional.
+	Errors *ErrorRegistry
+
+	// ErrorComponent is the component rendered in production with
+	// the "status" prop.
+	//
+	// It defaults to DefaultErrorComponent.
+	ErrorComponent string
+}
+
+func (c *ErrorPageConfig) defaults(renderer *Renderer) {
+	c.Logger = cmp.Or(c.Logger, renderer.Logger())
+	c.ErrorComponent = cmp.Or(c.ErrorComponent, DefaultErrorComponent)
+}
+
+// PanicError is an error created from a recovered panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("inertia: panic: %v", e.Value) }
+
+// Unwrap returns the panic value, if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// errorPage is an httperror.ErrorHandler rendering error pages.
+type errorPage struct {
+	renderer    *Renderer
+	config      ErrorPageConfig
+	development bool
+}
+
+// ErrorPageHandler returns an error handler rendering error pages for
+// both Inertia.js and regular requests.
+//
+// When running without -tags=production, it responds with an HTML page
+// describing the error: the stack trace of a PanicError, the request
+// headers, the partial reload state and the failing prop key of
+// a PropError. The Inertia.js client shows it in a modal.
+//
+// Otherwise, it renders ErrorPageConfig.ErrorComponent with the "status"
+// prop and the matching response status code.
+//
+// The status code and the component are resolved with
+// ErrorPageConfig.Errors. A component registered for an error is rendered
+// in development as well, unless the status code is 5xx.
+func ErrorPageHandler(renderer *Renderer, opts ...func(*ErrorPageConfig)) httperror.ErrorHandler {
+	return newErrorPage(renderer, development, opts...)
+}
+
+func newErrorPage(renderer *Renderer, development bool, opts ...func(*ErrorPageConfig)) *errorPage {
+	//nolint:exhaustruct
+	config := ErrorPageConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	config.defaults(renderer)
+
+	return &errorPage{
+		renderer:    renderer,
+		config:      config,
+		development: development,
+	}
+}
+
+// RecoverMiddleware recovers panics of the next handler and passes them
+// as PanicError to the ErrorPageHandler.
+//
+// If the response has been written already, the panic is only logged.
+// The http.ErrAbortHandler panic is not recovered.
+func RecoverMiddleware(renderer *Renderer, opts ...func(*ErrorPageConfig)) httpmiddleware.MiddlewareFunc {
+	return newErrorPage(renderer, development, opts...).recover
+}
+
+func (p *errorPage) recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoverWriter{ResponseWriter: w, written: false}
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			//nolint:errorlint,err113
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := &PanicError{Value: v, Stack: rdebug.Stack()}
+			if rw.written {
+				p.log(r, err, http.StatusInternalServerError)
+				return
+			}
+
+			p.ServeHTTP(w, r, err)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (p *errorPage) ServeHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	status, component := p.config.Errors.Lookup(err)
+
+	p.log(r, err, status)
+
+	h := w.Header()
+	h.Del(inertiaheader.HeaderXInertia)
+	h.Del(inertiaheader.HeaderETag)
+
+	if component != nil && (!p.development || status < http.StatusInternalServerError) {
+		p.render(w, r, err, status, component)
+		return
+	}
+
+	if p.development {
+		h.Set(inertiaheader.HeaderContentType, contentTypeHTML)
+		w.WriteHeader(status)
+
+		if err := errorPageTemplate.Execute(w, newErrorPageData(r, p.renderer.URL(r), err, status)); err != nil {
+			d("Failed to execute error page template: %v", err)
+		}
+
+		return
+	}
+
+	//nolint:exhaustruct
+	p.render(w, r, err, status, &ErrorComponent{Component: p.config.ErrorComponent})
+}
+
+// render renders the error component with the status code.
+func (p *errorPage) render(w http.ResponseWriter, r *http.Request, err error, status int, component *ErrorComponent) {
+	renderErr := p.renderer.Render(w, r, component.Component, NewRenderContext(
+		WithStatus(status),
+		WithProps(component.props(r, err, status)),
+	))
+	if renderErr != nil {
+		p.log(r, renderErr, http.StatusInternalServerError)
+		http.Error(w, http.StatusText(status), status)
+	}
+}
+
+// log logs the error handled with the status code.
+func (p *errorPage) log(r *http.Request, err error, status int) {
+	ctx := r.Context()
+
+	level := slog.LevelError
+	if status < http.StatusInternalServerError {
+		level = slog.LevelDebug
+	}
+
+	if !p.config.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.Any("error", err),
+		slog.Int("status", status),
+		slog.String("method", r.Method),
+		slog.String("url", p.renderer.URL(r)),
+	}
+
+	var propErr *PropError
+	if errors.As(err, &propErr) {
+		attrs = append(attrs, slog.String("prop", propErr.Key))
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		attrs = append(attrs, slog.String("stack", string(panicErr.Stack)))
+	}
+
+	p.config.Logger.LogAttrs(ctx, level, "inertia: request failed", attrs...)
+}
+
+// recoverWriter is a wrapper around http.ResponseWriter tracking
+// whether the response has been written.
+type recoverWriter struct {
+	http.ResponseWriter
+
+	written bool
+}
+
+func (w *recoverWriter) WriteHeader(code int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recoverWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+func (w *recoverWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// errorPageData is the data of the development error page.
+type errorPageData struct {
+	Error            string
+	Prop             string
+	Stack            string
+	Method           string
+	URL              string
+	Version          string
+	PartialComponent string
+	PartialData      []string
+	PartialExcept    []string
+	Reset            []string
+	ErrorBag         string
+	Headers          []errorPageHeader
+	Status           int
+	Inertia          bool
+}
+
+type errorPageHeader struct {
+	Key   string
+	Value string
+}
+
+func newErrorPageData(r *http.Request, url string, err error, status int) *errorPageData {
+	//nolint:exhaustruct
+	data := &errorPageData{
+		Error:            err.Error(),
+		Status:           status,
+		Method:           r.Method,
+		URL:              url,
+		Inertia:          isInertiaRequest(r),
+		Version:          r.Header.Get(inertiaheader.HeaderXInertiaVersion),
+		PartialComponent: r.Header.Get(inertiaheader.HeaderXInertiaPartialComponent),
+		PartialData:      extractHeaderValueList(r.Header.Get(inertiaheader.HeaderXInertiaPartialData)),
+		PartialExcept:    extractHeaderValueList(r.Header.Get(inertiaheader.HeaderXInertiaPartialExcept)),
+		Reset:            extractHeaderValueList(r.Header.Get(inertiaheader.HeaderXInertiaReset)),
+		ErrorBag:         r.Header.Get(inertiaheader.HeaderXInertiaErrorBag),
+	}
+
+	var propErr *PropError
+	if errors.As(err, &propErr) {
+		data.Prop = propErr.Key
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		data.Stack = string(panicErr.Stack)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(r.Header)) {
+		value := redacted
+		if !slices.Contains(redactedHeaders, key) {
+			value = r.Header.Get(key)
+		}
+
+		data.Headers = append(data.Headers, errorPageHeader{Key: key, Value: value})
+	}
+
+	return data
+}
+
+//nolint:gochecknoglobals
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.Error}}</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { color: #cf222e; font-size: 1.4rem; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5rem; }
+th, td { text-align: left; padding: .3rem .6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; width: 16rem; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Status}}: {{.Error}}</h1>
+{{with .Prop}}<p>Failed to resolve prop <code>{{.}}</code>.</p>{{end}}
+{{with .Stack}}<h2>Stack trace</h2>
+<pre>{{.}}</pre>{{end}}
+<h2>Request</h2>
+<table>
+<tr><th>Request</th><td>{{.Method}} {{.URL}}</td></tr>
+<tr><th>Inertia</th><td>{{.Inertia}}</td></tr>
+{{with .Version}}<tr><th>X-Inertia-Version</th><td>{{.}}</td></tr>{{end}}
+{{with .PartialComponent}}<tr><th>X-Inertia-Partial-Component</th><td>{{.}}</td></tr>{{end}}
+{{with .PartialData}}<tr><th>X-Inertia-Partial-Data</th><td>{{range $i, $v := .}}{{if $i}}, {{end}}{{$v}}{{end}}</td></tr>{{end}}
+{{with .PartialExcept}}<tr><th>X-Inertia-Partial-Except</th><td>{{range $i, $v := .}}{{if $i}}, {{end}}{{$v}}{{end}}</td></tr>{{end}}
+{{with .Reset}}<tr><th>X-Inertia-Reset</th><td>{{range $i, $v := .}}{{if $i}}, {{end}}{{$v}}{{end}}</td></tr>{{end}}
+{{with .ErrorBag}}<tr><th>X-Inertia-Error-Bag</th><td>{{.}}</td></tr>{{end}}
+</table>
+<h2>Headers</h2>
+<table>
+{{range .Headers}}<tr><th>{{.Key}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,154 @@
+package inertia
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{Version: "1"})
+
+	newHandler := func(page *errorPage, h http.HandlerFunc) http.Handler {
+		return page.recover(Middleware(renderer)(h))
+	}
+
+	panicking := func(http.ResponseWriter, *http.Request) { panic("boom") }
+
+	t.Run("development", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		page := newErrorPage(renderer, true, func(c *ErrorPageConfig) { c.Logger = newTestLogger(&buf) })
+		h := newHandler(page, panicking)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{
+			Inertia:          true,
+			Version:          "1",
+			PartialComponent: "Users",
+			Whitelist:        []string{"users"},
+		})
+		req.Header.Set("Cookie", "session=s3cr3t")
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get(inertiaheader.HeaderXInertia), "must not be an Inertia.js response")
+		assert.Equal(t, contentTypeHTML, w.Header().Get(inertiaheader.HeaderContentType))
+
+		body := w.Body.String()
+		assert.Contains(t, body, "inertia: panic: boom")
+		assert.Contains(t, body, "Stack trace")
+		assert.Contains(t, body, "errorpage_test.go")
+		assert.Contains(t, body, "<tr><th>X-Inertia-Partial-Data</th><td>users</td></tr>")
+		assert.Contains(t, body, "<tr><th>Cookie</th><td>[REDACTED]</td></tr>")
+		assert.NotContains(t, body, "s3cr3t")
+
+		assert.Contains(t, buf.String(), `"msg":"inertia: request failed"`)
+	})
+
+	t.Run("production", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, false, func(c *ErrorPageConfig) { c.ErrorComponent = "Errors/Server" })
+		h := newHandler(page, panicking)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{
+			Inertia: true,
+			Version: "1",
+		})
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "true", w.Header().Get(inertiaheader.HeaderXInertia))
+
+		var p Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, "Errors/Server", p.Component)
+		assert.Equal(t, float64(http.StatusInternalServerError), p.Props["status"])
+	})
+
+	t.Run("production html", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, false)
+		h := newHandler(page, panicking)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", nil)
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "&#34;component&#34;:&#34;Error&#34;")
+	})
+
+	t.Run("response already written", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, true)
+		h := page.recover(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			panic("boom")
+		}))
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("abort handler", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, true)
+		h := page.recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() { h.ServeHTTP(w, req) })
+	})
+}
+
+func TestErrorPageHandler_PropError(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, nil)
+	page := newErrorPage(renderer, true)
+	propErr := errors.New("database is down")
+
+	req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+		Inertia:          true,
+		PartialComponent: "Users",
+		Whitelist:        []string{"users"},
+	})
+
+	err := renderer.Render(w, req, "Users", NewRenderContext(
+		WithProps(NewOptional("users", LazyFunc(func(context.Context) (any, error) {
+			return nil, propErr
+		}))),
+	))
+	require.ErrorIs(t, err, propErr)
+
+	page.ServeHTTP(w, req, err)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Failed to resolve prop <code>users</code>.")
+	assert.NotContains(t, w.Body.String(), "Stack trace")
+}
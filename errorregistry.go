@@ -0,0 +1,107 @@
+package inertia
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorComponent is a component rendered for an error.
+type ErrorComponent struct {
+	// Props builds the props of the component.
+	//
+	// If Props is nil, the component receives the "status" prop.
+	Props func(r *http.Request, err error, status int) Proper
+
+	// Component is the name of the component.
+	Component string
+}
+
+// props returns the props of the component rendered for err.
+func (c *ErrorComponent) props(r *http.Request, err error, status int) Proper {
+	if c.Props == nil {
+		return NewAlways("status", status)
+	}
+
+	return c.Props(r, err, status)
+}
+
+// errorEntry is an error registered in an ErrorRegistry.
+type errorEntry struct {
+	target    error
+	component *ErrorComponent
+	status    int
+}
+
+// ErrorRegistry maps errors and status codes to components.
+//
+// Errors are matched with errors.Is in the order they were registered.
+// If no error matches, the status code is resolved from an error
+// implementing the StatusCoder interface, and the component registered
+// for the status code, if any, is used.
+//
+// To create a new ErrorRegistry, use the NewErrorRegistry function.
+type ErrorRegistry struct {
+	statuses map[int]*ErrorComponent
+	errors   []errorEntry
+}
+
+// StatusCoder is implemented by errors carrying an HTTP status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// NewErrorRegistry creates a new empty ErrorRegistry.
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{
+		statuses: make(map[int]*ErrorComponent),
+		errors:   nil,
+	}
+}
+
+// HandleError registers the component rendered with the status code
+// for errors matching target.
+//
+// If component is nil, the status code is still resolved for the target,
+// and the component registered for the status code is used.
+func (reg *ErrorRegistry) HandleError(target error, status int, component *ErrorComponent) *ErrorRegistry {
+	reg.errors = append(reg.errors, errorEntry{target: target, component: component, status: status})
+	return reg
+}
+
+// HandleStatus registers the component rendered for errors resolved
+// to the status code.
+func (reg *ErrorRegistry) HandleStatus(status int, component *ErrorComponent) *ErrorRegistry {
+	reg.statuses[status] = component
+	return reg
+}
+
+// Lookup resolves the status code and the component for err.
+//
+// If no component is registered, the returned component is nil.
+// If the status code can't be resolved, it defaults to
+// 500 Internal Server Error.
+func (reg *ErrorRegistry) Lookup(err error) (int, *ErrorComponent) {
+	status := http.StatusInternalServerError
+
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+
+	if reg == nil {
+		return status, nil
+	}
+
+	for _, e := range reg.errors {
+		if errors.Is(err, e.target) {
+			status = e.status
+			if e.component != nil {
+				return status, e.component
+			}
+
+			break
+		}
+	}
+
+	return status, reg.statuses[status]
+}
@@ -0,0 +1,172 @@
+package inertia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+var (
+	errNotFound  = errors.New("not found")
+	errForbidden = errors.New("forbidden")
+	errGone      = errors.New("gone")
+)
+
+type statusError int
+
+func (e statusError) Error() string   { return http.StatusText(int(e)) }
+func (e statusError) StatusCode() int { return int(e) }
+
+func TestErrorRegistry_Lookup(t *testing.T) {
+	t.Parallel()
+
+	notFound := &ErrorComponent{Component: "Errors/NotFound"}
+	forbidden := &ErrorComponent{Component: "Errors/Forbidden"}
+	tooMany := &ErrorComponent{Component: "Errors/TooManyRequests"}
+
+	reg := NewErrorRegistry().
+		HandleError(errNotFound, http.StatusNotFound, notFound).
+		HandleError(errForbidden, http.StatusForbidden, nil).
+		HandleStatus(http.StatusForbidden, forbidden).
+		HandleStatus(http.StatusTooManyRequests, tooMany)
+
+	tests := []struct {
+		err       error
+		component *ErrorComponent
+		name      string
+		status    int
+	}{
+		{name: "sentinel", err: errNotFound, status: http.StatusNotFound, component: notFound},
+		{
+			name:      "wrapped sentinel",
+			err:       fmt.Errorf("failed to load user: %w", errNotFound),
+			status:    http.StatusNotFound,
+			component: notFound,
+		},
+		{name: "sentinel with status component", err: errForbidden, status: http.StatusForbidden, component: forbidden},
+		{name: "status coder", err: statusError(http.StatusTooManyRequests), status: http.StatusTooManyRequests, component: tooMany},
+		{name: "status coder without component", err: statusError(http.StatusTeapot), status: http.StatusTeapot},
+		{name: "unknown", err: errGone, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, component := reg.Lookup(tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Same(t, tt.component, component)
+		})
+	}
+
+	t.Run("nil registry", func(t *testing.T) {
+		t.Parallel()
+
+		var reg *ErrorRegistry
+
+		status, component := reg.Lookup(statusError(http.StatusNotFound))
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Nil(t, component)
+	})
+}
+
+func TestErrorPageHandler_Registry(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, nil)
+	reg := NewErrorRegistry().
+		HandleError(errNotFound, http.StatusNotFound, &ErrorComponent{
+			Component: "Errors/NotFound",
+			Props: func(r *http.Request, _ error, status int) Proper {
+				return Props{
+					NewAlways("status", status),
+					NewAlways("path", r.URL.Path),
+				}
+			},
+		}).
+		HandleStatus(http.StatusServiceUnavailable, &ErrorComponent{Component: "Errors/Maintenance"})
+
+	withRegistry := func(c *ErrorPageConfig) { c.Errors = reg }
+
+	t.Run("inertia request", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, true, withRegistry)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users/42", &inertiatest.RequestConfig{Inertia: true})
+
+		page.ServeHTTP(w, req, fmt.Errorf("user 42: %w", errNotFound))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "true", w.Header().Get(inertiaheader.HeaderXInertia))
+
+		var p Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, "Errors/NotFound", p.Component)
+		assert.Equal(t, float64(http.StatusNotFound), p.Props["status"])
+		assert.Equal(t, "/users/42", p.Props["path"])
+	})
+
+	t.Run("full page request", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, true, withRegistry)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users/42", nil)
+
+		page.ServeHTTP(w, req, errNotFound)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "&#34;component&#34;:&#34;Errors/NotFound&#34;")
+	})
+
+	t.Run("server error in development", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, true, withRegistry)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		page.ServeHTTP(w, req, statusError(http.StatusServiceUnavailable))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Empty(t, w.Header().Get(inertiaheader.HeaderXInertia))
+		assert.Contains(t, w.Body.String(), "<h1>503: Service Unavailable</h1>")
+	})
+
+	t.Run("server error in production", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, false, withRegistry)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		page.ServeHTTP(w, req, statusError(http.StatusServiceUnavailable))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var p Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, "Errors/Maintenance", p.Component)
+		assert.Equal(t, float64(http.StatusServiceUnavailable), p.Props["status"])
+	})
+
+	t.Run("unmapped error in production", func(t *testing.T) {
+		t.Parallel()
+
+		page := newErrorPage(renderer, false, withRegistry)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		page.ServeHTTP(w, req, errGone)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var p Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, DefaultErrorComponent, p.Component)
+	})
+}
@@ -0,0 +1,90 @@
+package inertia
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+// etagSize is the number of bytes of the SHA-256 sum used in ETags.
+const etagSize = 16
+
+// etagVaryHeaders are the request headers that affect the encoded page.
+//
+//nolint:gochecknoglobals
+var etagVaryHeaders = []string{
+	inertiaheader.HeaderXInertiaPartialComponent,
+	inertiaheader.HeaderXInertiaPartialData,
+	inertiaheader.HeaderXInertiaPartialExcept,
+	inertiaheader.HeaderXInertiaReset,
+}
+
+// writeWithETag writes body to w with a strong ETag computed over body
+// and the partial reload headers of req.
+//
+// If the request is a GET or HEAD request with the If-None-Match header
+// matching the ETag, a 304 Not Modified response is sent without the body.
+func writeWithETag(w http.ResponseWriter, req *http.Request, body []byte) error {
+	etag := computeETag(req, body)
+
+	h := w.Header()
+	h.Set(inertiaheader.HeaderETag, etag)
+
+	for _, key := range etagVaryHeaders {
+		h.Add(inertiaheader.HeaderVary, key)
+	}
+
+	if (req.Method == http.MethodGet || req.Method == http.MethodHead) &&
+		etagMatch(req.Header.Get(inertiaheader.HeaderIfNoneMatch), etag) {
+		d("ETag %s matches, sending 304 Not Modified", etag)
+
+		h.Del(inertiaheader.HeaderContentType)
+		w.WriteHeader(http.StatusNotModified)
+
+		return nil
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("inertia: failed to write response: %w", err)
+	}
+
+	return nil
+}
+
+// computeETag computes a strong ETag over body and the partial reload
+// headers of req.
+func computeETag(req *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	for _, key := range etagVaryHeaders {
+		_, _ = hash.Write([]byte(req.Header.Get(key)))
+		_, _ = hash.Write([]byte{0})
+	}
+
+	_, _ = hash.Write(body)
+
+	return `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:etagSize]) + `"`
+}
+
+// etagMatch reports whether the If-None-Match header value matches etag
+// using the weak comparison.
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package inertia
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestRenderer_ETag(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{Version: "1.0.0", ETag: true})
+	renderCtx := NewRenderContext(WithProps(Props{
+		NewProp("title", "Title", nil),
+		NewProp("content", "Content", nil),
+	}))
+
+	render := func(t *testing.T, config *inertiatest.RequestConfig, ifNoneMatch string) (int, string, string) {
+		t.Helper()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", config)
+		if ifNoneMatch != "" {
+			req.Header.Set(inertiaheader.HeaderIfNoneMatch, ifNoneMatch)
+		}
+
+		require.NoError(t, renderer.Render(w, req, "Home", renderCtx))
+
+		return w.Code, w.Header().Get(inertiaheader.HeaderETag), w.Body.String()
+	}
+
+	for name, config := range map[string]*inertiatest.RequestConfig{
+		"json": {Inertia: true},
+		"html": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code, etag, body := render(t, config, "")
+			assert.Equal(t, http.StatusOK, code)
+			assert.NotEmpty(t, etag)
+			assert.NotEmpty(t, body)
+
+			code, etag2, body := render(t, config, etag)
+			assert.Equal(t, http.StatusNotModified, code)
+			assert.Equal(t, etag, etag2, "ETag must be stable")
+			assert.Empty(t, body)
+
+			code, _, _ = render(t, config, `"other", W/`+etag)
+			assert.Equal(t, http.StatusNotModified, code)
+
+			code, _, body = render(t, config, `"stale"`)
+			assert.Equal(t, http.StatusOK, code)
+			assert.NotEmpty(t, body)
+		})
+	}
+
+	t.Run("varies on partial reload headers", func(t *testing.T) {
+		t.Parallel()
+
+		_, etag, _ := render(t, &inertiatest.RequestConfig{Inertia: true}, "")
+
+		code, partialETag, body := render(t, &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Home",
+			Whitelist:        []string{"title", "content"},
+		}, etag)
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotEqual(t, etag, partialETag)
+		assert.NotEmpty(t, body)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+		require.NoError(t, renderer.Render(w, req, "Home", renderCtx))
+		assert.Contains(t, w.Header().Values(inertiaheader.HeaderVary), inertiaheader.HeaderXInertiaPartialData)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, nil)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+		req.Header.Set(inertiaheader.HeaderIfNoneMatch, "*")
+
+		require.NoError(t, renderer.Render(w, req, "Home", renderCtx))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(inertiaheader.HeaderETag))
+	})
+}
+
+func TestEtagMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		header   string
+		etag     string
+		expected bool
+	}{
+		{name: "empty", header: "", etag: `"a"`, expected: false},
+		{name: "exact", header: `"a"`, etag: `"a"`, expected: true},
+		{name: "weak", header: `W/"a"`, etag: `"a"`, expected: true},
+		{name: "list", header: `"b", "a"`, etag: `"a"`, expected: true},
+		{name: "wildcard", header: `*`, etag: `"a"`, expected: true},
+		{name: "mismatch", header: `"b"`, etag: `"a"`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, etagMatch(tt.header, tt.etag))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"log/slog"
 	"net/http"
 	"os"
@@ -33,10 +34,10 @@ const rootTemplate = `<!doctype html>
 </head>
 <body>
 	{{.InertiaBody}}
-	{{template "viteClient"}}
-	{{template "viteReactRefresh"}}
+	{{template "viteClient" .}}
+	{{template "viteReactRefresh" .}}
 
-	{{viteResource "src/main.tsx"}}
+	{{viteResource "src/main.tsx" .}}
 </body>
 </html>`
 
@@ -55,6 +56,20 @@ func setupPool(ctx context.Context, logger *slog.Logger, url string) *pgxpool.Po
 	return pool
 }
 
+// newSessionStore creates the inertiaframe session store with the key
+// read from the SESSION_KEY environment variable, hex encoded.
+//
+// The key is required when built with -tags=production.
+func newSessionStore() inertiaframe.SessionStore {
+	var keys [][]byte
+	if key := os.Getenv("SESSION_KEY"); key != "" {
+		keys = append(keys, must.Must(hex.DecodeString(key)))
+	}
+
+	//nolint:exhaustruct
+	return inertiaframe.NewCookieSessionStore(&inertiaframe.SessionConfig{Keys: keys})
+}
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
@@ -70,6 +85,10 @@ func main() {
 	)
 	middleware := inertia.Middleware(renderer)
 
+	sessions := newSessionStore()
+	//nolint:exhaustruct
+	mountOpts := &inertiaframe.MountOpts{SessionStore: sessions}
+
 	authenticator := serversession.New[user.Info, any](pool, nil)
 	passwordHandler := shieldpassword.NewHandler(pool, authenticator, sender.New(), nil)
 
@@ -89,18 +108,18 @@ func main() {
 	logoutHandler := serversession.NewLogoutHandler[user.Info, any](pool, nil)
 
 	// Sign up
-	inertiaframe.Mount(unprotectedMux, &endpoint.SignUpGetEndpoint{}, nil)
+	inertiaframe.Mount(unprotectedMux, &endpoint.SignUpGetEndpoint{}, mountOpts)
 	inertiaframe.Mount(unprotectedMux, &endpoint.SignUpPostEndpoint{
 		Handler:       passwordHandler,
 		Authenticator: authenticator,
-	}, nil)
+	}, mountOpts)
 
 	// Sign in
-	inertiaframe.Mount(unprotectedMux, &endpoint.SignInGetEndpoint{}, nil)
+	inertiaframe.Mount(unprotectedMux, &endpoint.SignInGetEndpoint{}, mountOpts)
 	inertiaframe.Mount(unprotectedMux, &endpoint.SignInPostEndpoint{
 		Handler:       passwordHandler,
 		Authenticator: authenticator,
-	}, nil)
+	}, mountOpts)
 
 	// Sign out
 	protectedMux.Get("/logout", func(w http.ResponseWriter, r *http.Request) {
@@ -113,7 +132,7 @@ func main() {
 	})
 
 	// Home
-	inertiaframe.Mount(protectedMux, &endpoint.HomeEndpoint{}, nil)
+	inertiaframe.Mount(protectedMux, &endpoint.HomeEndpoint{}, mountOpts)
 
 	mux := chi.NewMux()
 
@@ -122,7 +141,7 @@ func main() {
 
 	go func() {
 		//nolint:gosec
-		must.Must1(http.ListenAndServe(":8080", middleware.Middleware(mux)))
+		must.Must1(http.ListenAndServe(":8080", middleware.Middleware(inertiaframe.SessionMiddleware(sessions)(mux))))
 	}()
 
 	log.InfoContext(ctx, "Server is running", slog.String("addr", "http://localhost:8080"))
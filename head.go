@@ -0,0 +1,138 @@
+package inertia
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"go.inout.gg/foundations/must"
+)
+
+// HeadExtension is the page extension the head is sent with to the client.
+const HeadExtension = "head"
+
+// Head represents the document head elements managed on the server.
+//
+// The head is rendered into TemplateData.InertiaHead when server-side
+// rendering is disabled. Every rendered element carries the "inertia"
+// attribute, so the Inertia.js head manager removes the elements once
+// a page renders its own <Head> component, e.g., on client-side visits.
+//
+// The head is also sent to the client as the HeadExtension page extension,
+// e.g., {"head": {"title": "About"}}. The Inertia.js client doesn't read
+// it: to keep the head after hydration, the application must render
+// page.head with the <Head> component itself, e.g., in a persistent layout.
+type Head struct {
+	Title     string `json:"title,omitempty"`
+	Canonical string `json:"canonical,omitempty"`
+	Metas     []Meta `json:"metas,omitempty"`
+
+	// JSONLD is a list of JSON-LD documents rendered into
+	// <script type="application/ld+json"> elements.
+	//
+	// Each document must be JSON serializable.
+	JSONLD []any `json:"jsonLd,omitempty"`
+}
+
+// Meta represents a <meta> element.
+//
+// Either Name or Property should be set, e.g., Name: "description"
+// or Property: "og:title".
+type Meta struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"`
+	Content  string `json:"content"`
+}
+
+// WithHead sets the document title and appends the meta elements.
+//
+// Calling this function multiple times will override the title and
+// append the meta elements.
+func WithHead(title string, metas ...Meta) Option {
+	return func(renderCtx *RenderContext) {
+		renderCtx.Head.Title = title
+		renderCtx.Head.Metas = append(renderCtx.Head.Metas, metas...)
+	}
+}
+
+// WithCanonical sets the canonical URL of the page.
+func WithCanonical(url string) Option {
+	return func(renderCtx *RenderContext) { renderCtx.Head.Canonical = url }
+}
+
+// WithJSONLD appends a JSON-LD document to the page head.
+//
+// The v must be JSON serializable.
+func WithJSONLD(v any) Option {
+	return func(renderCtx *RenderContext) {
+		renderCtx.Head.JSONLD = append(renderCtx.Head.JSONLD, v)
+	}
+}
+
+// Empty reports whether the head has no elements.
+func (h *Head) Empty() bool {
+	return h.Title == "" && h.Canonical == "" && len(h.Metas) == 0 && len(h.JSONLD) == 0
+}
+
+// HTML renders the head elements.
+func (h *Head) HTML() (template.HTML, error) {
+	if h.Empty() {
+		return "", nil
+	}
+
+	var w strings.Builder
+
+	if h.Title != "" {
+		_ = must.Must(w.WriteString(`<title inertia>`))
+		_ = must.Must(w.WriteString(template.HTMLEscapeString(h.Title)))
+		_ = must.Must(w.WriteString(`</title>`))
+	}
+
+	for _, m := range h.Metas {
+		_ = must.Must(w.WriteString(`<meta inertia`))
+
+		if m.Name != "" {
+			writeAttr(&w, "name", m.Name)
+		}
+
+		if m.Property != "" {
+			writeAttr(&w, "property", m.Property)
+		}
+
+		writeAttr(&w, "content", m.Content)
+		_ = must.Must(w.WriteString(`>`))
+	}
+
+	if h.Canonical != "" {
+		_ = must.Must(w.WriteString(`<link inertia rel="canonical"`))
+		writeAttr(&w, "href", h.Canonical)
+		_ = must.Must(w.WriteString(`>`))
+	}
+
+	for _, v := range h.JSONLD {
+		// json.Marshal escapes <, > and &, so the document can't
+		// break out of the script element.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("inertia: failed to encode JSON-LD: %w", err)
+		}
+
+		_ = must.Must(w.WriteString(`<script inertia type="application/ld+json">`))
+		_ = must.Must(w.Write(b))
+		_ = must.Must(w.WriteString(`</script>`))
+	}
+
+	//nolint:gosec
+	return template.HTML(w.String()), nil
+}
+
+// writeAttr writes an HTML attribute with an escaped value, prefixed
+// with a space.
+func writeAttr(w *strings.Builder, key, value string) {
+	_ = must.Must(w.WriteRune(' '))
+	_ = must.Must(w.WriteString(key))
+	_ = must.Must(w.WriteString(`="`))
+	_ = must.Must(w.WriteString(template.HTMLEscapeString(value)))
+	_ = must.Must(w.WriteRune('"'))
+}
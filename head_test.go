@@ -0,0 +1,172 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestHead_HTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		head     Head
+		expected template.HTML
+	}{
+		{name: "empty", head: Head{}, expected: ""},
+		{
+			name:     "title",
+			head:     Head{Title: "Tom & Jerry"},
+			expected: `<title inertia>Tom &amp; Jerry</title>`,
+		},
+		{
+			name: "metas",
+			head: Head{Metas: []Meta{
+				{Name: "description", Content: `"quoted"`},
+				{Property: "og:title", Content: "Title"},
+			}},
+			expected: `<meta inertia name="description" content="&#34;quoted&#34;">` +
+				`<meta inertia property="og:title" content="Title">`,
+		},
+		{
+			name:     "canonical",
+			head:     Head{Canonical: "https://example.com/about"},
+			expected: `<link inertia rel="canonical" href="https://example.com/about">`,
+		},
+		{
+			name: "json-ld",
+			head: Head{JSONLD: []any{map[string]string{"name": "</script><script>"}}},
+			expected: `<script inertia type="application/ld+json">` +
+				`{"name":"\u003c/script\u003e\u003cscript\u003e"}</script>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			html, err := tt.head.HTML()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, html)
+		})
+	}
+
+	t.Run("invalid json-ld", func(t *testing.T) {
+		t.Parallel()
+
+		head := Head{JSONLD: []any{func() {}}}
+		_, err := head.HTML()
+		require.Error(t, err)
+	})
+}
+
+func TestRenderer_Head(t *testing.T) {
+	t.Parallel()
+
+	tpl := template.Must(template.New("root").Parse(`<head>{{ .InertiaHead }}</head>{{ .InertiaBody }}`))
+	opts := []Option{
+		WithHead("About", Meta{Name: "description", Content: "About us"}),
+		WithCanonical("https://example.com/about"),
+	}
+
+	t.Run("renders head without ssr", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(tpl, nil)
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/about", nil)
+
+		var buf bytes.Buffer
+
+		err := renderer.RenderHTML(t.Context(), &buf, req, "About", NewRenderContext(opts...))
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), `<head><title inertia>About</title>`+
+			`<meta inertia name="description" content="About us">`+
+			`<link inertia rel="canonical" href="https://example.com/about"></head>`)
+	})
+
+	t.Run("ssr head takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		ssrClient := NewMockSsrClient(ctrl)
+		ssrClient.EXPECT().Render(gomock.Any(), gomock.Any()).Return(&SsrTemplateData{
+			Head: "<title>SSR</title>",
+			Body: "<div>SSR</div>",
+		}, nil)
+
+		renderer := New(tpl, &Config{SsrClient: ssrClient})
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/about", nil)
+
+		var buf bytes.Buffer
+
+		err := renderer.RenderHTML(t.Context(), &buf, req, "About", NewRenderContext(opts...))
+		require.NoError(t, err)
+
+		assert.Equal(t, `<head><title>SSR</title></head><div>SSR</div>`, buf.String())
+	})
+}
+
+func TestRenderer_HeadExtension(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, nil)
+
+	t.Run("sent to the client", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/about", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "About", NewRenderContext(
+			WithHead("About", Meta{Name: "description", Content: "About us"}),
+			WithJSONLD(map[string]string{"@type": "Organization"}),
+		)))
+
+		var page map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.Equal(t, map[string]any{
+			"title":  "About",
+			"metas":  []any{map[string]any{"name": "description", "content": "About us"}},
+			"jsonLd": []any{map[string]any{"@type": "Organization"}},
+		}, page[HeadExtension])
+	})
+
+	t.Run("without head", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/about", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "About", NewRenderContext()))
+
+		var page map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.NotContains(t, page, HeadExtension)
+	})
+
+	t.Run("extension takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/about", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "About", NewRenderContext(
+			WithHead("About"),
+			WithExtension(HeadExtension, "custom"),
+		)))
+
+		var page map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.Equal(t, "custom", page[HeadExtension])
+	})
+}
@@ -0,0 +1,195 @@
+package inertia
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Hooks is a set of callbacks called during the render lifecycle.
+//
+// Hooks can be used to bridge the renderer with tracing and metrics.
+// Any of the callbacks may be nil. The callbacks can be called
+// concurrently, e.g., when props are resolved concurrently.
+type Hooks struct {
+	// RenderStart is called before the page is rendered.
+	//
+	// The returned context is used for the rest of the render, e.g.,
+	// to resolve props, so that it can carry a tracing span.
+	// If RenderStart returns nil, the original context is used.
+	RenderStart func(context.Context, RenderStartInfo) context.Context
+
+	// RenderDone is called after the page is rendered or the render failed.
+	RenderDone func(context.Context, RenderDoneInfo)
+
+	// PropResolved is called after each prop value is resolved.
+	PropResolved func(context.Context, PropResolvedInfo)
+
+	// SsrDone is called after the SsrClient returns.
+	SsrDone func(context.Context, SsrDoneInfo)
+
+	// TemplateExecuted is called after the root template is executed.
+	TemplateExecuted func(context.Context, TemplateExecutedInfo)
+
+	// VersionMismatch is called by the Middleware when the client asset
+	// version doesn't match the Renderer version.
+	VersionMismatch func(context.Context, VersionMismatchInfo)
+
+	// BudgetExceeded is called when the page exceeds Config.MaxPropSize
+	// or Config.MaxPageSize.
+	BudgetExceeded func(context.Context, BudgetExceededInfo)
+}
+
+// RenderStartInfo is the information passed to Hooks.RenderStart.
+type RenderStartInfo struct {
+	Request   *http.Request
+	Component string
+}
+
+// RenderDoneInfo is the information passed to Hooks.RenderDone.
+type RenderDoneInfo struct {
+	Request *http.Request
+
+	// Page is the rendered page, it is nil if Err is not nil.
+	Page *Page
+
+	Err       error
+	Component string
+	Duration  time.Duration
+}
+
+// PropResolvedInfo is the information passed to Hooks.PropResolved.
+type PropResolvedInfo struct {
+	Err      error
+	Key      string
+	Duration time.Duration
+
+	// Concurrent reports whether the prop was resolved concurrently.
+	Concurrent bool
+
+	// Lazy reports whether the prop is lazy, i.e., optional or deferred.
+	Lazy bool
+
+	Deferred  bool
+	Merge     bool
+	Sensitive bool
+}
+
+// SsrDoneInfo is the information passed to Hooks.SsrDone.
+type SsrDoneInfo struct {
+	Err error
+
+	// Data is the SsrClient output, it is nil if Err is not nil.
+	Data *SsrTemplateData
+
+	Component string
+	Duration  time.Duration
+}
+
+// TemplateExecutedInfo is the information passed to Hooks.TemplateExecuted.
+type TemplateExecutedInfo struct {
+	Err error
+
+	// Template is the name of the executed root template.
+	Template string
+
+	Component string
+	Duration  time.Duration
+}
+
+// VersionMismatchInfo is the information passed to Hooks.VersionMismatch.
+type VersionMismatchInfo struct {
+	Request       *http.Request
+	ClientVersion string
+	ServerVersion string
+}
+
+// ChainHooks returns hooks calling each of the hooks in order, e.g.,
+// to combine tracing hooks with inertiadevtools.
+//
+// The context returned by a RenderStart callback is passed to the next
+// one. Nil hooks are skipped.
+func ChainHooks(hooks ...*Hooks) *Hooks {
+	hooks = slices.DeleteFunc(slices.Clone(hooks), func(h *Hooks) bool { return h == nil })
+
+	switch len(hooks) {
+	case 0:
+		return nil
+	case 1:
+		return hooks[0]
+	}
+
+	return &Hooks{
+		RenderStart: func(ctx context.Context, info RenderStartInfo) context.Context {
+			for _, h := range hooks {
+				ctx = h.renderStart(ctx, info)
+			}
+
+			return ctx
+		},
+		RenderDone:       chainHook(hooks, (*Hooks).renderDone),
+		PropResolved:     chainHook(hooks, (*Hooks).propResolved),
+		SsrDone:          chainHook(hooks, (*Hooks).ssrDone),
+		TemplateExecuted: chainHook(hooks, (*Hooks).templateExecuted),
+		VersionMismatch:  chainHook(hooks, (*Hooks).versionMismatch),
+		BudgetExceeded:   chainHook(hooks, (*Hooks).budgetExceeded),
+	}
+}
+
+// chainHook returns a callback calling fn with each of the hooks.
+func chainHook[T any](hooks []*Hooks, fn func(*Hooks, context.Context, T)) func(context.Context, T) {
+	return func(ctx context.Context, info T) {
+		for _, h := range hooks {
+			fn(h, ctx, info)
+		}
+	}
+}
+
+func (h *Hooks) renderStart(ctx context.Context, info RenderStartInfo) context.Context {
+	if h == nil || h.RenderStart == nil {
+		return ctx
+	}
+
+	if newCtx := h.RenderStart(ctx, info); newCtx != nil {
+		return newCtx
+	}
+
+	return ctx
+}
+
+func (h *Hooks) renderDone(ctx context.Context, info RenderDoneInfo) {
+	if h != nil && h.RenderDone != nil {
+		h.RenderDone(ctx, info)
+	}
+}
+
+func (h *Hooks) propResolved(ctx context.Context, info PropResolvedInfo) {
+	if h != nil && h.PropResolved != nil {
+		h.PropResolved(ctx, info)
+	}
+}
+
+func (h *Hooks) ssrDone(ctx context.Context, info SsrDoneInfo) {
+	if h != nil && h.SsrDone != nil {
+		h.SsrDone(ctx, info)
+	}
+}
+
+func (h *Hooks) templateExecuted(ctx context.Context, info TemplateExecutedInfo) {
+	if h != nil && h.TemplateExecuted != nil {
+		h.TemplateExecuted(ctx, info)
+	}
+}
+
+func (h *Hooks) versionMismatch(ctx context.Context, info VersionMismatchInfo) {
+	if h != nil && h.VersionMismatch != nil {
+		h.VersionMismatch(ctx, info)
+	}
+}
+
+func (h *Hooks) budgetExceeded(ctx context.Context, info BudgetExceededInfo) {
+	if h != nil && h.BudgetExceeded != nil {
+		h.BudgetExceeded(ctx, info)
+	}
+}
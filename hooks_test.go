@@ -0,0 +1,281 @@
+package inertia
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+type hookRecorder struct {
+	renderStarts []RenderStartInfo
+	renderDones  []RenderDoneInfo
+	props        []PropResolvedInfo
+	ssrs         []SsrDoneInfo
+	templates    []TemplateExecutedInfo
+	mismatches   []VersionMismatchInfo
+	mu           sync.Mutex
+}
+
+type hookCtxKey struct{}
+
+func (rec *hookRecorder) hooks() *Hooks {
+	return &Hooks{
+		RenderStart: func(ctx context.Context, info RenderStartInfo) context.Context {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.renderStarts = append(rec.renderStarts, info)
+
+			return context.WithValue(ctx, hookCtxKey{}, "span")
+		},
+		RenderDone: func(_ context.Context, info RenderDoneInfo) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.renderDones = append(rec.renderDones, info)
+		},
+		PropResolved: func(_ context.Context, info PropResolvedInfo) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.props = append(rec.props, info)
+		},
+		SsrDone: func(_ context.Context, info SsrDoneInfo) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.ssrs = append(rec.ssrs, info)
+		},
+		TemplateExecuted: func(_ context.Context, info TemplateExecutedInfo) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.templates = append(rec.templates, info)
+		},
+		VersionMismatch: func(_ context.Context, info VersionMismatchInfo) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.mismatches = append(rec.mismatches, info)
+		},
+	}
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("html render", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Hooks: rec.hooks()})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(
+			WithProps(Props{
+				NewProp("title", "Home", nil),
+				NewDeferred("stats", LazyFunc(func(context.Context) (any, error) {
+					return nil, nil
+				}), nil),
+			}),
+		)))
+
+		require.Len(t, rec.renderStarts, 1)
+		assert.Equal(t, "Home", rec.renderStarts[0].Component)
+		assert.Same(t, req, rec.renderStarts[0].Request)
+
+		require.Len(t, rec.renderDones, 1)
+		require.NoError(t, rec.renderDones[0].Err)
+		assert.Equal(t, "Home", rec.renderDones[0].Component)
+		require.NotNil(t, rec.renderDones[0].Page)
+		assert.Equal(t, "Home", rec.renderDones[0].Page.Component)
+
+		concurrent := map[string]bool{}
+		for _, p := range rec.props {
+			concurrent[p.Key] = p.Concurrent
+		}
+
+		assert.Equal(t, map[string]bool{"title": false, "errors": false}, concurrent,
+			"deferred props must not be resolved")
+
+		require.Len(t, rec.templates, 1)
+		assert.Equal(t, "test", rec.templates[0].Template)
+		assert.Equal(t, "Home", rec.templates[0].Component)
+		require.NoError(t, rec.templates[0].Err)
+
+		assert.Empty(t, rec.ssrs)
+	})
+
+	t.Run("partial concurrent props", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Hooks: rec.hooks()})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Home",
+			Whitelist:        []string{"a", "b"},
+		})
+
+		var spanValue any
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(
+			WithProps(Props{
+				NewDeferred("a", LazyFunc(func(context.Context) (any, error) {
+					return 1, nil
+				}), &DeferredOptions{Concurrent: true}),
+				NewOptional("b", LazyFunc(func(ctx context.Context) (any, error) {
+					spanValue = ctx.Value(hookCtxKey{})
+					return 2, nil
+				})),
+			}),
+		)))
+
+		concurrent := map[string]bool{}
+		for _, p := range rec.props {
+			concurrent[p.Key] = p.Concurrent
+		}
+
+		assert.Equal(t, map[string]bool{"a": true, "b": false, "errors": false}, concurrent)
+		assert.Equal(t, "span", spanValue, "props must be resolved with the RenderStart context")
+		assert.Empty(t, rec.templates, "template must not be executed for Inertia.js requests")
+	})
+
+	t.Run("prop error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Hooks: rec.hooks()})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Home",
+			Whitelist:        []string{"broken"},
+		})
+		propErr := errors.New("prop error")
+
+		err := renderer.Render(w, req, "Home", NewRenderContext(
+			WithProps(NewOptional("broken", LazyFunc(func(context.Context) (any, error) {
+				return nil, propErr
+			}))),
+		))
+		require.ErrorIs(t, err, propErr)
+
+		i := slices.IndexFunc(rec.props, func(p PropResolvedInfo) bool { return p.Key == "broken" })
+		require.GreaterOrEqual(t, i, 0)
+		require.ErrorIs(t, rec.props[i].Err, propErr)
+
+		require.Len(t, rec.renderDones, 1)
+		require.ErrorIs(t, rec.renderDones[0].Err, propErr)
+		assert.Nil(t, rec.renderDones[0].Page)
+	})
+
+	t.Run("ssr", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		ssrErr := errors.New("SSR error")
+		client := NewMockSsrClient(ctrl)
+		client.EXPECT().Render(gomock.Any(), gomock.Any()).Return(nil, ssrErr)
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Hooks: rec.hooks(), SsrClient: client})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.ErrorIs(t, renderer.Render(w, req, "Home", NewRenderContext()), ssrErr)
+
+		require.Len(t, rec.ssrs, 1)
+		assert.Equal(t, "Home", rec.ssrs[0].Component)
+		require.ErrorIs(t, rec.ssrs[0].Err, ssrErr)
+		assert.Empty(t, rec.templates)
+	})
+
+	t.Run("render page", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Hooks: rec.hooks()})
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		_, err := renderer.RenderPage(t.Context(), req, "Home", NewRenderContext())
+		require.NoError(t, err)
+
+		assert.Len(t, rec.renderStarts, 1)
+		assert.Len(t, rec.renderDones, 1)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &hookRecorder{}
+		renderer := New(testTpl, &Config{Version: "2", Hooks: rec.hooks()})
+		h := Middleware(renderer)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia: true,
+			Version: "1",
+		})
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		require.Len(t, rec.mismatches, 1)
+		assert.Equal(t, "1", rec.mismatches[0].ClientVersion)
+		assert.Equal(t, "2", rec.mismatches[0].ServerVersion)
+	})
+}
+
+func TestChainHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("calls each hooks", func(t *testing.T) {
+		t.Parallel()
+
+		first, second := &hookRecorder{}, &hookRecorder{}
+
+		type spanKey struct{}
+
+		var spans []any
+
+		renderer := New(testTpl, &Config{Hooks: ChainHooks(
+			first.hooks(),
+			nil,
+			&Hooks{
+				RenderStart: func(ctx context.Context, _ RenderStartInfo) context.Context {
+					return context.WithValue(ctx, spanKey{}, "child")
+				},
+				RenderDone: func(ctx context.Context, _ RenderDoneInfo) {
+					spans = append(spans, ctx.Value(hookCtxKey{}), ctx.Value(spanKey{}))
+				},
+			},
+			second.hooks(),
+		)})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(
+			WithProps(NewProp("title", "Home", nil)),
+		)))
+
+		for _, rec := range []*hookRecorder{first, second} {
+			assert.Len(t, rec.renderStarts, 1)
+			assert.Len(t, rec.renderDones, 1)
+			assert.Len(t, rec.props, 2)
+			assert.Len(t, rec.templates, 1)
+		}
+
+		assert.Equal(t, []any{"span", "child"}, spans, "contexts must be passed down the chain")
+	})
+
+	t.Run("nil hooks", func(t *testing.T) {
+		t.Parallel()
+
+		h := &Hooks{}
+
+		assert.Nil(t, ChainHooks())
+		assert.Nil(t, ChainHooks(nil, nil))
+		assert.Same(t, h, ChainHooks(nil, h))
+	})
+}
@@ -0,0 +1,404 @@
+//go:build !production
+
+package inertiadevtools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.inout.gg/foundations/debug"
+	"go.inout.gg/foundations/http/httpmiddleware"
+
+	"go.inout.gg/inertia"
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+var d = debug.Debuglog("inertia/devtools") //nolint:gochecknoglobals
+
+// redacted is the value shown in place of sensitive props.
+const redacted = "[REDACTED]"
+
+type entryCtxKey struct{}
+
+//nolint:gochecknoglobals
+var kEntryCtxKey = entryCtxKey{}
+
+// entry is a render being recorded.
+type entry struct {
+	record Record
+
+	// middleware reports whether the entry is created by the middleware,
+	// in which case the middleware stores the record once the response
+	// is written.
+	middleware bool
+
+	started bool
+	mu      sync.Mutex
+}
+
+// New creates a new Devtools instance.
+//
+// If config is nil, the default configuration is used.
+func New(config *Config) *Devtools {
+	if config == nil {
+		//nolint:exhaustruct
+		config = &Config{}
+	}
+
+	config.defaults()
+
+	debug.Assert(config.Capacity > 0, "expected Capacity to be positive")
+
+	//nolint:exhaustruct
+	return &Devtools{
+		records:  make([]*Record, 0, config.Capacity),
+		capacity: config.Capacity,
+	}
+}
+
+// Hooks returns the hooks recording renders.
+//
+// Set them as inertia.Config.Hooks of the Renderer to inspect. To keep
+// the hooks of the application, e.g., for tracing, combine them with
+// inertia.ChainHooks:
+//
+//	renderer := inertia.New(t, &inertia.Config{Hooks: inertia.ChainHooks(hooks, dt.Hooks())})
+func (dt *Devtools) Hooks() *inertia.Hooks {
+	return &inertia.Hooks{
+		RenderStart:      dt.renderStart,
+		RenderDone:       dt.renderDone,
+		PropResolved:     dt.propResolved,
+		SsrDone:          dt.ssrDone,
+		TemplateExecuted: dt.templateExecuted,
+		VersionMismatch:  nil,
+	}
+}
+
+// Middleware returns a middleware associating renders with the requests.
+//
+// Renders are recorded once the response is written, along with the
+// response status code.
+func (dt *Devtools) Middleware() httpmiddleware.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			//nolint:exhaustruct
+			e := &entry{middleware: true}
+			sw := &statusWriter{ResponseWriter: w, statusCode: 0}
+
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), kEntryCtxKey, e)))
+
+			if !e.started {
+				return
+			}
+
+			e.record.StatusCode = cmp.Or(sw.statusCode, http.StatusOK)
+			dt.add(&e.record)
+		})
+	}
+}
+
+// Records returns the recorded renders, the most recent first.
+func (dt *Devtools) Records() []Record {
+	dt.mu.RLock()
+	defer dt.mu.RUnlock()
+
+	records := make([]Record, 0, len(dt.records))
+	for i := range len(dt.records) {
+		idx := (dt.next - 1 - i + len(dt.records)) % len(dt.records)
+		records = append(records, *dt.records[idx])
+	}
+
+	return records
+}
+
+// Record returns the recorded render with the given ID.
+func (dt *Devtools) Record(id uint64) (Record, bool) {
+	dt.mu.RLock()
+	defer dt.mu.RUnlock()
+
+	for _, r := range dt.records {
+		if r.ID == id {
+			return *r, true
+		}
+	}
+
+	//nolint:exhaustruct
+	return Record{}, false
+}
+
+// Handler returns a handler serving the HTML UI to browse the recorded
+// renders.
+//
+// The handler serves the list of renders at "/" and a single render
+// at "/{id}", so it must be mounted with http.StripPrefix under a path
+// ending with a slash, e.g., "/_inertia/".
+func (dt *Devtools) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		w.Header().Set(inertiaheader.HeaderContentType, "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+
+		p := strings.Trim(r.URL.Path, "/")
+		if p == "" {
+			dt.writeHTML(w, "list", dt.Records())
+			return
+		}
+
+		id, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		record, ok := dt.Record(id)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		dt.writeHTML(w, "record", record)
+	})
+}
+
+func (dt *Devtools) writeHTML(w http.ResponseWriter, name string, data any) {
+	if err := uiTemplate.ExecuteTemplate(w, name, data); err != nil {
+		d("failed to execute %s template: %v", name, err)
+	}
+}
+
+// add stores the record in the ring buffer.
+func (dt *Devtools) add(r *Record) {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	dt.lastID++
+	r.ID = dt.lastID
+
+	if len(dt.records) < dt.capacity {
+		dt.records = append(dt.records, r)
+	} else {
+		dt.records[dt.next] = r
+	}
+
+	dt.next = (dt.next + 1) % dt.capacity
+}
+
+func (dt *Devtools) renderStart(ctx context.Context, info inertia.RenderStartInfo) context.Context {
+	e, ok := ctx.Value(kEntryCtxKey).(*entry)
+	if !ok || e.started {
+		// A standalone render, or a subsequent render within the same request.
+		//nolint:exhaustruct
+		e = &entry{}
+	}
+
+	e.started = true
+	e.record.Time = time.Now()
+	e.record.Component = info.Component
+	e.record.Request = newRequestRecord(info.Request, info.Component)
+
+	return context.WithValue(ctx, kEntryCtxKey, e)
+}
+
+func (dt *Devtools) renderDone(ctx context.Context, info inertia.RenderDoneInfo) {
+	e, ok := ctx.Value(kEntryCtxKey).(*entry)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.record.Duration = info.Duration
+
+	if info.Err != nil {
+		e.record.Error = info.Err.Error()
+	}
+
+	if page := info.Page; page != nil {
+		e.record.DeferredProps = page.DeferredProps
+		e.record.MergeProps = page.MergeProps
+		e.record.PropsJSON = propsJSON(page.Props, e.record.Props)
+
+		for i, p := range e.record.Props {
+			if v, ok := page.Props[p.Key]; ok {
+				e.record.Props[i].Size = jsonSize(v)
+			}
+		}
+	}
+
+	slices.SortFunc(e.record.Props, func(a, b PropRecord) int { return strings.Compare(a.Key, b.Key) })
+
+	if !e.middleware {
+		dt.add(&e.record)
+	}
+}
+
+func (dt *Devtools) propResolved(ctx context.Context, info inertia.PropResolvedInfo) {
+	e, ok := ctx.Value(kEntryCtxKey).(*entry)
+	if !ok {
+		return
+	}
+
+	//nolint:exhaustruct
+	p := PropRecord{
+		Key:        info.Key,
+		Duration:   info.Duration,
+		Concurrent: info.Concurrent,
+		Lazy:       info.Lazy,
+		Deferred:   info.Deferred,
+		Merge:      info.Merge,
+		Sensitive:  info.Sensitive,
+	}
+
+	if info.Err != nil {
+		p.Error = info.Err.Error()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.record.Props = append(e.record.Props, p)
+}
+
+func (dt *Devtools) ssrDone(ctx context.Context, info inertia.SsrDoneInfo) {
+	e, ok := ctx.Value(kEntryCtxKey).(*entry)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.record.SSR = info.Data
+	e.record.SsrDuration = info.Duration
+
+	// The SSR output embeds the props, so it is redacted as a whole if
+	// any of them is sensitive.
+	if info.Data != nil && slices.ContainsFunc(e.record.Props, func(p PropRecord) bool { return p.Sensitive }) {
+		e.record.SSR = &inertia.SsrTemplateData{Head: redacted, Body: redacted}
+	}
+}
+
+func (dt *Devtools) templateExecuted(ctx context.Context, info inertia.TemplateExecutedInfo) {
+	e, ok := ctx.Value(kEntryCtxKey).(*entry)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.record.Template = info.Template
+	e.record.TemplateDuration = info.Duration
+}
+
+// newRequestRecord creates a RequestRecord from the request rendering
+// the component.
+func newRequestRecord(req *http.Request, componentName string) RequestRecord {
+	h := req.Header
+
+	//nolint:exhaustruct
+	r := RequestRecord{
+		Method:           req.Method,
+		URL:              inertia.RequestURL(req),
+		Inertia:          h.Get(inertiaheader.HeaderXInertia) == "true",
+		Version:          h.Get(inertiaheader.HeaderXInertiaVersion),
+		PartialComponent: h.Get(inertiaheader.HeaderXInertiaPartialComponent),
+		ErrorBag:         h.Get(inertiaheader.HeaderXInertiaErrorBag),
+		Reset:            headerValueList(h.Get(inertiaheader.HeaderXInertiaReset)),
+	}
+
+	if r.PartialComponent == componentName {
+		r.PartialData = headerValueList(h.Get(inertiaheader.HeaderXInertiaPartialData))
+		r.PartialExcept = headerValueList(h.Get(inertiaheader.HeaderXInertiaPartialExcept))
+	}
+
+	return r
+}
+
+// headerValueList splits a comma-separated header value.
+func headerValueList(v string) []string {
+	if v == "" {
+		return nil
+	}
+
+	values := strings.Split(v, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+
+	return values
+}
+
+// propsJSON returns the indented JSON of props with the sensitive
+// props redacted.
+func propsJSON(props map[string]any, records []PropRecord) string {
+	m := maps.Clone(props)
+	for _, p := range records {
+		if _, ok := m[p.Key]; ok && p.Sensitive {
+			m[p.Key] = redacted
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "failed to encode props: " + err.Error()
+	}
+
+	return string(b)
+}
+
+// jsonSize returns the size of the JSON encoding of v in bytes.
+func jsonSize(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+
+	return len(b)
+}
+
+var (
+	_ http.ResponseWriter                       = (*statusWriter)(nil)
+	_ interface{ Unwrap() http.ResponseWriter } = (*statusWriter)(nil)
+)
+
+// statusWriter is a wrapper around http.ResponseWriter recording
+// the response status code.
+type statusWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.statusCode == 0 {
+		w.statusCode = code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,37 @@
+//go:build production
+
+package inertiadevtools
+
+import (
+	"net/http"
+
+	"go.inout.gg/foundations/http/httpmiddleware"
+
+	"go.inout.gg/inertia"
+)
+
+// New creates a new Devtools instance that doesn't record anything.
+func New(config *Config) *Devtools {
+	//nolint:exhaustruct
+	return &Devtools{}
+}
+
+// Hooks returns nil.
+func (dt *Devtools) Hooks() *inertia.Hooks { return nil }
+
+// Middleware returns a middleware that does nothing.
+func (dt *Devtools) Middleware() httpmiddleware.MiddlewareFunc {
+	return func(next http.Handler) http.Handler { return next }
+}
+
+// Records returns nil.
+func (dt *Devtools) Records() []Record { return nil }
+
+// Record always reports false.
+func (dt *Devtools) Record(id uint64) (Record, bool) {
+	//nolint:exhaustruct
+	return Record{}, false
+}
+
+// Handler returns a handler responding with 404 Not Found.
+func (dt *Devtools) Handler() http.Handler { return http.NotFoundHandler() }
@@ -0,0 +1,276 @@
+//go:build !production
+
+package inertiadevtools
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+//nolint:gochecknoglobals
+var tpl = template.Must(template.New("test").Parse(`<html><body>{{ .InertiaBody }}</body></html>`))
+
+type ssrClientFunc func(context.Context, *inertia.Page) (*inertia.SsrTemplateData, error)
+
+func (fn ssrClientFunc) Render(ctx context.Context, p *inertia.Page) (*inertia.SsrTemplateData, error) {
+	return fn(ctx, p)
+}
+
+func newHandler(t *testing.T, dt *Devtools) http.Handler {
+	t.Helper()
+
+	renderer := inertia.New(tpl, &inertia.Config{Version: "1", Hooks: dt.Hooks()})
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inertia.MustRender(w, r, "Users", inertia.NewRenderContext(
+			inertia.WithProps(inertia.Props{
+				inertia.NewProp("users", []string{"alice", "bob"}, nil),
+				inertia.NewProp("token", "s3cr3t", &inertia.PropOptions{Sensitive: true}),
+				inertia.NewDeferred("stats", inertia.LazyFunc(func(context.Context) (any, error) {
+					return map[string]int{"total": 2}, nil
+				}), &inertia.DeferredOptions{Merge: true}),
+			}),
+		))
+	}))
+
+	return dt.Middleware()(inertia.Middleware(renderer)(mux))
+}
+
+func TestDevtools(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records html render", func(t *testing.T) {
+		t.Parallel()
+
+		dt := New(nil)
+		h := newHandler(t, dt)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", nil)
+		h.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		records := dt.Records()
+		require.Len(t, records, 1)
+
+		r := records[0]
+		assert.Equal(t, uint64(1), r.ID)
+		assert.Equal(t, "Users", r.Component)
+		assert.Equal(t, http.StatusOK, r.StatusCode)
+		assert.Equal(t, "test", r.Template)
+		assert.Equal(t, "GET", r.Request.Method)
+		assert.Equal(t, "/users", r.Request.URL)
+		assert.False(t, r.Request.Inertia)
+		assert.Empty(t, r.Error)
+		assert.Equal(t, map[string][]string{"default": {"stats"}}, r.DeferredProps)
+
+		keys := make([]string, 0, len(r.Props))
+		for _, p := range r.Props {
+			keys = append(keys, p.Key)
+		}
+
+		assert.Equal(t, []string{"errors", "token", "users"}, keys, "props must be sorted")
+		assert.Equal(t, len(`["alice","bob"]`), r.Props[2].Size)
+		assert.True(t, r.Props[1].Sensitive)
+
+		assert.Contains(t, r.PropsJSON, `"token": "[REDACTED]"`)
+		assert.NotContains(t, r.PropsJSON, "s3cr3t")
+	})
+
+	t.Run("records partial reload", func(t *testing.T) {
+		t.Parallel()
+
+		dt := New(nil)
+		h := newHandler(t, dt)
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{
+			Inertia:          true,
+			Version:          "1",
+			PartialComponent: "Users",
+			Whitelist:        []string{"stats"},
+		})
+		h.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		records := dt.Records()
+		require.Len(t, records, 1)
+
+		r := records[0]
+		assert.True(t, r.Request.Inertia)
+		assert.Equal(t, "1", r.Request.Version)
+		assert.Equal(t, "Users", r.Request.PartialComponent)
+		assert.Equal(t, []string{"stats"}, r.Request.PartialData)
+		assert.Equal(t, []string{"stats"}, r.MergeProps)
+		assert.Empty(t, r.Template)
+
+		var stats *PropRecord
+		for i := range r.Props {
+			if r.Props[i].Key == "stats" {
+				stats = &r.Props[i]
+			}
+		}
+
+		require.NotNil(t, stats)
+		assert.True(t, stats.Lazy)
+		assert.True(t, stats.Deferred)
+		assert.True(t, stats.Merge)
+	})
+
+	t.Run("without middleware", func(t *testing.T) {
+		t.Parallel()
+
+		dt := New(nil)
+		renderer := inertia.New(tpl, &inertia.Config{Hooks: dt.Hooks()})
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, renderer.Render(w, req, "Home", inertia.NewRenderContext()))
+
+		records := dt.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "Home", records[0].Component)
+		assert.Zero(t, records[0].StatusCode)
+	})
+
+	t.Run("ssr output", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			props    inertia.Props
+			expected *inertia.SsrTemplateData
+		}{
+			{
+				name:     "kept",
+				props:    inertia.Props{inertia.NewProp("name", "alice", nil)},
+				expected: &inertia.SsrTemplateData{Head: "<title>SSR</title>", Body: "<div>SSR</div>"},
+			},
+			{
+				name:     "redacted with sensitive props",
+				props:    inertia.Props{inertia.NewProp("token", "s3cr3t", &inertia.PropOptions{Sensitive: true})},
+				expected: &inertia.SsrTemplateData{Head: redacted, Body: redacted},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				dt := New(nil)
+				renderer := inertia.New(tpl, &inertia.Config{Hooks: dt.Hooks(), SsrClient: ssrClientFunc(
+					func(context.Context, *inertia.Page) (*inertia.SsrTemplateData, error) {
+						return &inertia.SsrTemplateData{Head: "<title>SSR</title>", Body: "<div>SSR</div>"}, nil
+					},
+				)})
+
+				req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+				require.NoError(t, renderer.Render(w, req, "Home", inertia.NewRenderContext(inertia.WithProps(tt.props))))
+
+				records := dt.Records()
+				require.Len(t, records, 1)
+				assert.Equal(t, tt.expected, records[0].SSR)
+			})
+		}
+	})
+
+	t.Run("chained hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var done int
+
+		dt := New(nil)
+		renderer := inertia.New(tpl, &inertia.Config{Hooks: inertia.ChainHooks(
+			&inertia.Hooks{RenderDone: func(context.Context, inertia.RenderDoneInfo) { done++ }},
+			dt.Hooks(),
+		)})
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, renderer.Render(w, req, "Home", inertia.NewRenderContext()))
+
+		assert.Equal(t, 1, done)
+		assert.Len(t, dt.Records(), 1)
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		t.Parallel()
+
+		dt := New(&Config{Capacity: 2})
+		h := newHandler(t, dt)
+
+		for range 3 {
+			req, w := inertiatest.NewRequest(http.MethodGet, "/users", nil)
+			h.ServeHTTP(w, req)
+		}
+
+		records := dt.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, uint64(3), records[0].ID, "most recent first")
+		assert.Equal(t, uint64(2), records[1].ID)
+
+		_, ok := dt.Record(1)
+		assert.False(t, ok, "oldest record must be evicted")
+	})
+}
+
+func TestDevtools_Handler(t *testing.T) {
+	t.Parallel()
+
+	dt := New(nil)
+	h := newHandler(t, dt)
+
+	req, w := inertiatest.NewRequest(http.MethodGet, "/users", nil)
+	h.ServeHTTP(w, req)
+
+	ui := http.StripPrefix("/_inertia", dt.Handler())
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		ui.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_inertia/", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `<a href="./1">1</a>`)
+		assert.Contains(t, w.Body.String(), "Users")
+	})
+
+	t.Run("record", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		ui.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_inertia/1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Users <small>#1</small>")
+		assert.Contains(t, w.Body.String(), "[REDACTED]")
+		assert.NotContains(t, w.Body.String(), "s3cr3t")
+	})
+
+	t.Run("unknown record", func(t *testing.T) {
+		t.Parallel()
+
+		for _, path := range []string{"/_inertia/42", "/_inertia/abc"} {
+			w := httptest.NewRecorder()
+			ui.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+		ui.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/_inertia/", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
@@ -0,0 +1,129 @@
+// Package inertiadevtools implements a development inspector for
+// Inertia.js renders.
+//
+// Devtools records the last renders of a Renderer: the component, the
+// resolved props with their sizes and timings, the partial reload headers
+// received and the SSR output. The records can be browsed with a small
+// HTML UI served by Devtools.Handler.
+//
+// Devtools is meant to be used in development only. Sensitive props, and
+// the SSR output of pages with sensitive props, are redacted, but other
+// props are kept in memory and shown as is.
+// When running with -tags=production, Devtools doesn't record anything,
+// Devtools.Hooks returns nil and Devtools.Handler responds with
+// 404 Not Found.
+//
+// To use Devtools, pass its hooks to the Renderer and mount its handler:
+//
+//	dt := inertiadevtools.New(nil)
+//	renderer := inertia.New(t, &inertia.Config{Hooks: dt.Hooks()})
+//
+//	mux.Handle("/_inertia/", http.StripPrefix("/_inertia", dt.Handler()))
+//	h := dt.Middleware()(inertia.Middleware(renderer)(mux))
+//
+// To keep existing hooks, combine them with inertia.ChainHooks.
+//
+// Devtools.Middleware is optional. It associates renders with the
+// requests, e.g., to record the response status code.
+package inertiadevtools
+
+import (
+	"cmp"
+	"sync"
+	"time"
+
+	"go.inout.gg/inertia"
+)
+
+// DefaultCapacity is the default number of recorded renders.
+const DefaultCapacity = 50
+
+// Config is the configuration for Devtools.
+type Config struct {
+	// Capacity is the number of the last renders kept.
+	//
+	// It defaults to DefaultCapacity.
+	Capacity int
+}
+
+func (c *Config) defaults() {
+	c.Capacity = cmp.Or(c.Capacity, DefaultCapacity)
+}
+
+// Devtools records the last Inertia.js renders.
+//
+// To create a new Devtools, use the New function.
+type Devtools struct {
+	records  []*Record // ring buffer
+	capacity int
+	next     int
+	lastID   uint64
+	mu       sync.RWMutex
+}
+
+// Record is a recorded render.
+type Record struct {
+	Time time.Time
+
+	// SSR is the output of the SsrClient, if any.
+	//
+	// It is redacted if the page has sensitive props.
+	SSR *inertia.SsrTemplateData
+
+	DeferredProps map[string][]string
+
+	// Error is the render error, if any.
+	Error string
+
+	Component string
+
+	// Template is the name of the executed root template, if any.
+	Template string
+
+	// PropsJSON is the indented JSON of the page props with the
+	// sensitive props redacted.
+	PropsJSON string
+
+	Request    RequestRecord
+	Props      []PropRecord
+	MergeProps []string
+
+	ID uint64
+
+	// StatusCode is the response status code, it is set only if the
+	// request went through Devtools.Middleware.
+	StatusCode int
+
+	Duration         time.Duration
+	SsrDuration      time.Duration
+	TemplateDuration time.Duration
+}
+
+// RequestRecord describes the request of a recorded render.
+type RequestRecord struct {
+	Method           string
+	URL              string
+	Version          string
+	PartialComponent string
+	ErrorBag         string
+	PartialData      []string
+	PartialExcept    []string
+	Reset            []string
+	Inertia          bool
+}
+
+// PropRecord describes a resolved prop of a recorded render.
+type PropRecord struct {
+	Key   string
+	Error string
+
+	// Size is the size of the JSON encoded prop value in bytes.
+	Size int
+
+	Duration   time.Duration
+	Concurrent bool
+	Lazy       bool
+	Deferred   bool
+	Merge      bool
+	Sensitive  bool
+}
@@ -0,0 +1,133 @@
+//go:build !production
+
+package inertiadevtools
+
+import (
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+//nolint:gochecknoglobals
+var uiTemplate = template.Must(template.New("ui").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"size": formatSize,
+}).Parse(uiTemplates))
+
+// formatSize formats n bytes in a human-readable form.
+func formatSize(n int) string {
+	const kib = 1024
+
+	if n < kib {
+		return strconv.Itoa(n) + " B"
+	}
+
+	return strconv.FormatFloat(float64(n)/kib, 'f', 1, 64) + " KiB"
+}
+
+const uiTemplates = `
+{{define "head"}}<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Inertia devtools</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5rem; }
+th, td { text-align: left; padding: .3rem .6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; }
+pre { background: #f6f8fa; padding: 1rem; overflow: auto; max-height: 30rem; }
+.error { color: #cf222e; }
+.tag { display: inline-block; padding: 0 .4rem; margin-right: .2rem; border-radius: .6rem; background: #ddf4ff; font-size: 12px; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "foot"}}</body>
+</html>
+{{end}}
+
+{{define "list"}}{{template "head"}}
+<h1>Inertia renders</h1>
+{{if .}}
+<table>
+<tr><th>#</th><th>Time</th><th>Request</th><th>Component</th><th>Type</th><th>Status</th><th>Duration</th></tr>
+{{range .}}
+<tr>
+<td><a href="./{{.ID}}">{{.ID}}</a></td>
+<td>{{.Time.Format "15:04:05.000"}}</td>
+<td>{{.Request.Method}} {{.Request.URL}}</td>
+<td>{{.Component}}</td>
+<td>{{if .Request.PartialData}}partial{{else if .Request.PartialExcept}}partial{{else if .Request.Inertia}}inertia{{else}}html{{end}}</td>
+<td>{{if .Error}}<span class="error">error</span>{{else if .StatusCode}}{{.StatusCode}}{{end}}</td>
+<td>{{.Duration}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No renders recorded yet.</p>
+{{end}}
+{{template "foot"}}{{end}}
+
+{{define "record"}}{{template "head"}}
+<p><a href="./">&larr; All renders</a></p>
+<h1>{{.Component}} <small>#{{.ID}}</small></h1>
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+
+<h2>Request</h2>
+<table>
+<tr><th>Request</th><td>{{.Request.Method}} {{.Request.URL}}</td></tr>
+<tr><th>Time</th><td>{{.Time.Format "2006-01-02 15:04:05.000"}}</td></tr>
+{{with .StatusCode}}<tr><th>Status</th><td>{{.}}</td></tr>{{end}}
+<tr><th>Inertia</th><td>{{.Request.Inertia}}</td></tr>
+{{with .Request.Version}}<tr><th>X-Inertia-Version</th><td>{{.}}</td></tr>{{end}}
+{{with .Request.PartialComponent}}<tr><th>X-Inertia-Partial-Component</th><td>{{.}}</td></tr>{{end}}
+{{with .Request.PartialData}}<tr><th>X-Inertia-Partial-Data</th><td>{{join . ", "}}</td></tr>{{end}}
+{{with .Request.PartialExcept}}<tr><th>X-Inertia-Partial-Except</th><td>{{join . ", "}}</td></tr>{{end}}
+{{with .Request.Reset}}<tr><th>X-Inertia-Reset</th><td>{{join . ", "}}</td></tr>{{end}}
+{{with .Request.ErrorBag}}<tr><th>X-Inertia-Error-Bag</th><td>{{.}}</td></tr>{{end}}
+</table>
+
+<h2>Timings</h2>
+<table>
+<tr><th>Render</th><td>{{.Duration}}</td></tr>
+{{with .SsrDuration}}<tr><th>SSR</th><td>{{.}}</td></tr>{{end}}
+{{if .Template}}<tr><th>Template {{.Template}}</th><td>{{.TemplateDuration}}</td></tr>{{end}}
+</table>
+
+<h2>Props</h2>
+{{if .Props}}
+<table>
+<tr><th>Key</th><th>Size</th><th>Duration</th><th>Flags</th></tr>
+{{range .Props}}
+<tr>
+<td>{{.Key}}</td>
+<td>{{size .Size}}</td>
+<td>{{.Duration}}</td>
+<td>{{if .Lazy}}<span class="tag">lazy</span>{{end}}{{if .Deferred}}<span class="tag">deferred</span>{{end}}{{if .Merge}}<span class="tag">merge</span>{{end}}{{if .Concurrent}}<span class="tag">concurrent</span>{{end}}{{if .Sensitive}}<span class="tag">sensitive</span>{{end}}{{with .Error}}<span class="error">{{.}}</span>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No props resolved.</p>
+{{end}}
+{{with .DeferredProps}}
+<h3>Deferred props</h3>
+<table>
+<tr><th>Group</th><th>Props</th></tr>
+{{range $group, $keys := .}}<tr><td>{{$group}}</td><td>{{join $keys ", "}}</td></tr>{{end}}
+</table>
+{{end}}
+{{with .MergeProps}}<p>Merge props: {{join . ", "}}</p>{{end}}
+{{with .PropsJSON}}<pre>{{.}}</pre>{{end}}
+
+{{with .SSR}}
+<h2>SSR output</h2>
+<h3>Head</h3>
+<pre>{{.Head}}</pre>
+<h3>Body</h3>
+<pre>{{.Body}}</pre>
+{{end}}
+{{template "foot"}}{{end}}
+`
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"mime"
 	"net/http"
 	"reflect"
@@ -64,43 +65,81 @@ func WithProps(r *http.Request, props inertia.Proper) *http.Request {
 // RedirectBack redirects the user back to the previous page.
 //
 // The previous page is determined from the Referer header and
-// falls back to the session if the header is not present.
+// falls back to the session if the header is not present, see
+// TrackPathMiddleware.
+//
+// If the previous page is unknown or is not an allowed redirect target,
+// the user is redirected to the fallback URL, see inertia.SafeRedirectURL.
 func RedirectBack(w http.ResponseWriter, r *http.Request) {
 	referer := r.Header.Get(inertiaheader.HeaderReferer)
 	if referer == "" {
 		sess, err := sessionFromRequest(r)
 		if err != nil {
-			d("failed to get session from request, using default '/'")
-
-			referer = "/"
+			d("failed to get session from request, using the fallback URL")
 		} else {
 			referer = sess.Referer()
 		}
 	}
 
+	referer = inertia.SafeRedirectURL(r, referer)
+
 	d("redirecting back to %s", referer)
 
-	inertiaredirect.Redirect(w, r, referer)
+	inertiaredirect.Redirect(w, r, referer, inertia.LoggerFromRequest(r))
 }
 
 // DefaultValidationErrorHandler is a default error handler for validation errors.
 //
 // It saves flash messages and redirects back to the previous page.
+//
+// If the validation errors can't be saved, e.g., a session exceeding
+// the cookie size without SessionConfig.Fallback, the failure is logged
+// and the user is redirected back without them.
 func DefaultValidationErrorHandler(w http.ResponseWriter, r *http.Request, errorer inertia.ValidationErrorer) {
-	errorBag := inertia.ErrorBagFromRequest(r)
-	sess := must.Must(sessionFromRequest(r))
+	errs := errorer.ValidationErrors()
 
-	sess.ErrorBag_ = errorBag
-	sess.ValidationErrors_ = errorer.ValidationErrors()
+	if err := saveValidationErrors(w, r, errs); err != nil {
+		d("failed to save validation errors: %v", err)
 
-	must.Must1(sess.Save(w))
+		inertia.LoggerFromRequest(r).LogAttrs(r.Context(), slog.LevelWarn, "inertiaframe: failed to save validation errors",
+			slog.Int("count", len(errs)),
+			slog.Any("error", err),
+		)
+	}
 
 	RedirectBack(w, r)
 }
 
+// saveValidationErrors saves the validation errors to the session.
+func saveValidationErrors(w http.ResponseWriter, r *http.Request, errs []inertia.ValidationError) error {
+	sess, err := sessionFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	sess.ErrorBag_ = inertia.ErrorBagFromRequest(r)
+	sess.SetValidationErrors(errs)
+
+	return sess.Save(w, r)
+}
+
+// DefaultErrorHandler is the default error handler.
+//
+// It handles validation errors with DefaultValidationErrorHandler and
+// falls back to httperror.DefaultErrorHandler.
+//
 //nolint:gochecknoglobals
-var DefaultErrorHandler httperror.ErrorHandler = httperror.ErrorHandlerFunc(
-	func(w http.ResponseWriter, r *http.Request, err error) {
+var DefaultErrorHandler = NewErrorHandler(httperror.DefaultErrorHandler)
+
+// NewErrorHandler creates an error handler that handles validation errors
+// with DefaultValidationErrorHandler and passes other errors to fallback.
+//
+// Use inertia.ErrorPageHandler as fallback to render error components,
+// e.g., for 404 Not Found, instead of plain text responses.
+func NewErrorHandler(fallback httperror.ErrorHandler) httperror.ErrorHandler {
+	debug.Assert(fallback != nil, "expected fallback to be defined")
+
+	return httperror.ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request, err error) {
 		var errorer inertia.ValidationErrorer
 		if errors.As(err, &errorer) {
 			DefaultValidationErrorHandler(w, r, errorer)
@@ -113,9 +152,9 @@ var DefaultErrorHandler httperror.ErrorHandler = httperror.ErrorHandlerFunc(
 			return
 		}
 
-		httperror.DefaultErrorHandler(w, r, err)
-	},
-)
+		fallback.ServeHTTP(w, r, err)
+	})
+}
 
 const (
 	mediaTypeJSON      = "application/json"
@@ -261,7 +300,7 @@ type redirectMessage struct{ url string }
 func (m *redirectMessage) Component() string { return "" }
 
 func (m *redirectMessage) Write(w http.ResponseWriter, r *http.Request) error {
-	RedirectBack(w, r)
+	inertia.Redirect(w, r, m.url)
 	return nil
 }
 
@@ -363,6 +402,29 @@ type MountOpts struct {
 	// ErrorHandler is the error handler used to handle errors.
 	// If ErrorHandler is nil, the DefaultErrorHandler will be used.
 	ErrorHandler httperror.ErrorHandler
+
+	// Logger is used to log structured records about handled requests.
+	// If Logger is nil, the logger of the inertia.Renderer handling
+	// the request will be used.
+	Logger *slog.Logger
+
+	// SessionStore is the store of the session carrying validation errors
+	// across redirects, e.g., an application-owned database store.
+	// If SessionStore is nil, a CookieSessionStore configured with Session
+	// will be used.
+	SessionStore SessionStore
+
+	// Session configures the default CookieSessionStore.
+	// If both SessionStore and Session are nil, the store attached by
+	// SessionMiddleware, or a CookieSessionStore with the default
+	// configuration will be used.
+	//
+	// When built with -tags=production, either SessionStore or Session
+	// with Keys is required, and Mount panics otherwise. To share the store
+	// attached by SessionMiddleware, pass it as SessionStore.
+	//
+	// Endpoints sharing the session must use the same configuration.
+	Session *SessionConfig
 }
 
 // Mount mounts the executor on the given mux.
@@ -397,7 +459,12 @@ func Mount[M any](mux Mux, e Endpoint[M], opts *MountOpts) {
 
 	d("Mounting executor on pattern: %s", pattern)
 
-	h := newHandler(e, opts.ErrorHandler, opts.Validator, opts.FormDecoder)
+	store, err := mountSessionStore(opts, development)
+	if err != nil {
+		panic(err)
+	}
+
+	h := newHandler(e, opts.ErrorHandler, opts.Validator, opts.FormDecoder, opts.Logger, store)
 	if opts.Middleware != nil {
 		h = opts.Middleware.Middleware(h)
 	}
@@ -405,16 +472,38 @@ func Mount[M any](mux Mux, e Endpoint[M], opts *MountOpts) {
 	mux.Handle(pattern, h)
 }
 
+// mountSessionStore returns the session store of the mounted endpoint.
+//
+// When built with -tags=production, a store or keys must be configured,
+// so a misconfigured application fails on startup rather than on requests.
+func mountSessionStore(opts *MountOpts, development bool) (SessionStore, error) {
+	if opts.SessionStore != nil {
+		return opts.SessionStore, nil
+	}
+
+	if opts.Session != nil {
+		return newCookieSessionStore(opts.Session)
+	}
+
+	if !development {
+		return nil, errSessionStoreRequired
+	}
+
+	return nil, nil //nolint:nilnil // the store attached to the request is used
+}
+
 // newHandler creates a new http.Handler for the given endpoint.
 func newHandler[M any](
 	endpoint Endpoint[M],
 	errorHandler httperror.ErrorHandler,
 	validate *validator.Validate,
 	formDecoder *form.Decoder,
+	logger *slog.Logger,
+	sessions SessionStore,
 ) http.Handler {
 	handleError := httperror.WithErrorHandler(errorHandler)
 
-	return handleError(httperror.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+	h := handleError(httperror.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
 		var (
 			msg       M
 			renderCtx inertia.RenderContext
@@ -422,6 +511,11 @@ func newHandler[M any](
 
 		ctx := r.Context()
 
+		logger := logger
+		if logger == nil {
+			logger = inertia.LoggerFromRequest(r)
+		}
+
 		if extract, ok := any(msg).(RawRequestExtractor); ok {
 			if err := extract.Extract(r); err != nil {
 				return fmt.Errorf("inertiaframe: failed to extract request data: %w", err)
@@ -461,6 +555,12 @@ func newHandler[M any](
 		if err := validate.StructCtx(ctx, &msg); err != nil {
 			d("failed to validate request")
 
+			logger.LogAttrs(ctx, slog.LevelDebug, "inertiaframe: failed to validate request",
+				slog.String("pattern", r.Pattern),
+				slog.String("error_bag", inertia.ErrorBagFromRequest(r)),
+				slog.Any("error", err),
+			)
+
 			return fmt.Errorf("inertiaframe: failed to validate request: %w", err)
 		}
 
@@ -468,12 +568,21 @@ func newHandler[M any](
 
 		resp, err := endpoint.Execute(ctx, req)
 		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelDebug, "inertiaframe: failed to execute endpoint",
+				slog.String("pattern", r.Pattern),
+				slog.Any("error", err),
+			)
+
 			return fmt.Errorf("inertiaframe: failed to execute: %w", err)
 		}
 
 		if resp == nil {
 			d("received empty response")
 
+			logger.LogAttrs(ctx, slog.LevelError, "inertiaframe: received empty response",
+				slog.String("pattern", r.Pattern),
+			)
+
 			return errors.New("inertiaframe: empty response")
 		}
 
@@ -508,12 +617,26 @@ func newHandler[M any](
 
 		renderCtx.Props = props
 
-		sess, _ := sessionFromRequest(r)
+		sess, err := sessionFromRequest(r)
+		if err != nil {
+			return err
+		}
+
 		errors := sess.ValidationErrors()
 
 		if errors != nil {
 			renderCtx.ErrorBag = sess.ErrorBag()
 			renderCtx.AddValidationErrorer(inertia.ValidationErrors(errors))
+
+			// Validation errors are shown once.
+			if err := sess.Save(w, r); err != nil {
+				return err
+			}
+
+			logger.LogAttrs(ctx, slog.LevelDebug, "inertiaframe: restored validation errors from session",
+				slog.String("error_bag", renderCtx.ErrorBag),
+				slog.Int("count", len(errors)),
+			)
 		}
 
 		componentName := resp.m.Component()
@@ -525,6 +648,12 @@ func newHandler[M any](
 
 		return nil
 	}))
+
+	if sessions == nil {
+		return h
+	}
+
+	return SessionMiddleware(sessions)(h)
 }
 
 // extractProps extracts props from the given message.
@@ -1,16 +1,10 @@
 package inertiaframe
 
 import (
-	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"sync"
-	"time"
-
-	"go.inout.gg/foundations/http/httpcookie"
 
 	"go.inout.gg/inertia"
 )
@@ -19,53 +13,81 @@ type sessCtx struct{}
 
 var kSessCtx = sessCtx{} //nolint:gochecknoglobals
 
+type sessRefCtx struct{}
+
+var kSessRefCtx = sessRefCtx{} //nolint:gochecknoglobals
+
+// sessionRef shares the session loaded by a handler with the middleware
+// up the chain, e.g., TrackPathMiddleware, as requests derived by the
+// handler don't propagate their context back.
+type sessionRef struct{ sess *session }
+
 const (
 	SessionCookieName = "_inertiaframe"
 	SessionPath       = "/"
 )
 
-//nolint:gochecknoglobals
-var bufPool = sync.Pool{New: func() any { return bytes.NewBuffer(nil) }}
-
-//nolint:gochecknoinits
-func init() {
-	gob.Register(&session{}) //nolint:exhaustruct
-	gob.Register([]inertia.ValidationError(nil))
-}
-
 // Session is a user's local session for storing informational data for
 // inertiaframe to work correctly.
 //
 // It is primarily used to store information about validation errors
 // and the last visited path.
 type session struct {
-	ErrorBag_         string                    //nolint:revive
-	Path_             string                    //nolint:revive
-	ValidationErrors_ []inertia.ValidationError //nolint:revive
+	store SessionStore
+
+	ErrorBag_         string                   `json:"error_bag,omitempty"`         //nolint:revive
+	Path_             string                   `json:"path,omitempty"`              //nolint:revive
+	ValidationErrors_ []sessionValidationError `json:"validation_errors,omitempty"` //nolint:revive
+}
+
+// sessionValidationError is a validation error stored in the session.
+type sessionValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // sessionFromRequest retrieves a session from the request. If the session
 // does not exist, a new session is created.
+//
+// The session is loaded from the SessionStore attached to the request,
+// or the default CookieSessionStore. Malformed session data is ignored.
 func sessionFromRequest(r *http.Request) (*session, error) {
 	sess, ok := r.Context().Value(kSessCtx).(*session)
 	if ok && sess != nil {
 		return sess, nil
 	}
 
-	val := httpcookie.Get(r, SessionCookieName)
-	if val == "" {
-		//nolint:exhaustruct
-		return &session{}, nil
+	ref, _ := r.Context().Value(kSessRefCtx).(*sessionRef)
+	if ref != nil && ref.sess != nil {
+		return ref.sess, nil
+	}
+
+	store, err := sessionStoreFromRequest(r)
+	if err != nil {
+		return nil, err
 	}
 
-	b, err := base64.RawURLEncoding.DecodeString(val)
+	data, err := store.Load(r)
 	if err != nil {
-		return nil, fmt.Errorf("inertiaframe: failed to decode session cookie: %w", err)
+		return nil, fmt.Errorf("inertiaframe: failed to load session: %w", err)
+	}
+
+	//nolint:exhaustruct
+	sess = &session{}
+
+	if data != nil {
+		if err := json.Unmarshal(data, sess); err != nil {
+			d("ignoring malformed session: %v", err)
+
+			//nolint:exhaustruct
+			sess = &session{}
+		}
 	}
 
-	sess = &session{} //nolint:exhaustruct
-	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(sess); err != nil {
-		return nil, fmt.Errorf("inertiaframe: failed to decode session: %w", err)
+	sess.store = store
+
+	if ref != nil {
+		ref.sess = sess
 	}
 
 	// Save session for future requests.
@@ -79,12 +101,28 @@ func sessionFromRequest(r *http.Request) (*session, error) {
 //
 // Once the errors are accessed, they are cleared from the session.
 func (s *session) ValidationErrors() []inertia.ValidationError {
-	ret := s.ValidationErrors_
+	if s.ValidationErrors_ == nil {
+		return nil
+	}
+
+	ret := make([]inertia.ValidationError, len(s.ValidationErrors_))
+	for i, err := range s.ValidationErrors_ {
+		ret[i] = inertia.NewValidationError(err.Field, err.Message)
+	}
+
 	s.ValidationErrors_ = nil
 
 	return ret
 }
 
+// SetValidationErrors stores the validation errors in the session.
+func (s *session) SetValidationErrors(errors []inertia.ValidationError) {
+	s.ValidationErrors_ = make([]sessionValidationError, len(errors))
+	for i, err := range errors {
+		s.ValidationErrors_[i] = sessionValidationError{Field: err.Field(), Message: err.Error()}
+	}
+}
+
 // ErrorBag returns the error bag associated with the request
 // that produced validation errors.
 func (s *session) ErrorBag() string {
@@ -97,39 +135,29 @@ func (s *session) ErrorBag() string {
 // Referer returns the last path visited by the user.
 //
 // It is used to redirect the user back to the previously visited page
-// when calling inertiaframe.RedirectBack.
+// when calling inertiaframe.RedirectBack. It is recorded by
+// TrackPathMiddleware.
 func (s *session) Referer() string { return s.Path_ }
 
 // Clear completely removes the session from the client.
-func (s *session) Clear(w http.ResponseWriter, r *http.Request) {
-	httpcookie.Delete(w, r, SessionCookieName)
-}
-
-// Save saves the session to the client, typically via a cookie.
-func (s *session) Save(w http.ResponseWriter) error {
-	buf := bufPool.Get().(*bytes.Buffer) //nolint:forcetypeassert
+func (s *session) Clear(w http.ResponseWriter, r *http.Request) error {
+	if err := s.store.Clear(w, r); err != nil {
+		return fmt.Errorf("inertiaframe: failed to clear session: %w", err)
+	}
 
-	defer func() {
-		bufPool.Put(buf)
-		buf.Reset()
-	}()
+	return nil
+}
 
-	err := gob.NewEncoder(buf).Encode(s)
+// Save saves the session to the SessionStore.
+func (s *session) Save(w http.ResponseWriter, r *http.Request) error {
+	data, err := json.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("inertiaframe: failed to encode session: %w", err)
 	}
 
-	//nolint:exhaustruct
-	cookie := &http.Cookie{
-		Name:     SessionCookieName,
-		Value:    base64.RawURLEncoding.EncodeToString(buf.Bytes()),
-		Path:     SessionPath,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Expires:  time.Now(),
+	if err := s.store.Save(w, r, data); err != nil {
+		return fmt.Errorf("inertiaframe: failed to save session: %w", err)
 	}
 
-	http.SetCookie(w, cookie)
-
 	return nil
 }
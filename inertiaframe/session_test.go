@@ -0,0 +1,477 @@
+package inertiaframe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia"
+)
+
+func newTestKey(b byte) []byte { return bytes.Repeat([]byte{b}, SessionKeySize) }
+
+// roundTrip saves the session to the save store and loads it back from
+// the load store with the cookies of the response.
+func roundTrip(t *testing.T, save, load SessionStore, sess *session) (*session, []*http.Cookie) {
+	t.Helper()
+
+	sess.store = save
+
+	w := httptest.NewRecorder()
+	require.NoError(t, sess.Save(w, httptest.NewRequest(http.MethodPost, "/", nil)))
+
+	cookies := w.Result().Cookies()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range cookies {
+		if c.MaxAge >= 0 {
+			r.AddCookie(c)
+		}
+	}
+
+	loaded, err := sessionFromRequest(withSessionStore(r, load))
+	require.NoError(t, err)
+
+	return loaded, cookies
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]SessionStore{
+		"cookie": NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}}),
+		"memory": NewMemorySessionStore(nil),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			//nolint:exhaustruct
+			sess := &session{ErrorBag_: "login", Path_: "/users"}
+			sess.SetValidationErrors([]inertia.ValidationError{inertia.NewValidationError("email", "is required")})
+
+			loaded, cookies := roundTrip(t, store, store, sess)
+
+			require.Len(t, cookies, 1)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Equal(t, int(DefaultSessionMaxAge.Seconds()), cookies[0].MaxAge)
+			assert.NotContains(t, cookies[0].Value, "users")
+
+			assert.Equal(t, "/users", loaded.Referer())
+			assert.Equal(t, "login", loaded.ErrorBag())
+
+			errs := loaded.ValidationErrors()
+			require.Len(t, errs, 1)
+			assert.Equal(t, "email", errs[0].Field())
+			assert.Equal(t, "is required", errs[0].Error())
+			assert.Nil(t, loaded.ValidationErrors(), "validation errors must be cleared once read")
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(cookies[0])
+
+			w := httptest.NewRecorder()
+			require.NoError(t, loaded.Clear(w, r))
+
+			cleared := w.Result().Cookies()
+			require.NotEmpty(t, cleared)
+			assert.Equal(t, -1, cleared[0].MaxAge)
+		})
+	}
+
+	t.Run("store error", func(t *testing.T) {
+		t.Parallel()
+
+		errStore := errors.New("database is down")
+		r := withSessionStore(httptest.NewRequest(http.MethodGet, "/", nil), failingSessionStore{errStore})
+
+		_, err := sessionFromRequest(r)
+		require.ErrorIs(t, err, errStore)
+
+		w := httptest.NewRecorder()
+		RedirectBack(w, r)
+
+		assert.Equal(t, inertia.DefaultFallbackRedirectURL, w.Header().Get("Location"))
+	})
+}
+
+func TestCookieSessionStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}})
+
+	t.Run("key rotation", func(t *testing.T) {
+		t.Parallel()
+
+		rotated := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(2), newTestKey(1)}})
+
+		//nolint:exhaustruct
+		loaded, _ := roundTrip(t, store, rotated, &session{Path_: "/users"})
+		assert.Equal(t, "/users", loaded.Referer())
+
+		//nolint:exhaustruct
+		loaded, _ = roundTrip(t, rotated, store, &session{Path_: "/users"})
+		assert.Empty(t, loaded.Referer(), "old key must not decrypt cookies encrypted with the new key")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		t.Parallel()
+
+		other := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(3)}})
+
+		//nolint:exhaustruct
+		loaded, _ := roundTrip(t, other, store, &session{Path_: "/users"})
+		assert.Empty(t, loaded.Referer())
+	})
+
+	t.Run("tampered cookie", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []string{"garbage", "AAAA", strings.Repeat("A", 64)} {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(&http.Cookie{Name: SessionCookieName, Value: value})
+
+			data, err := store.Load(r)
+			require.NoError(t, err)
+			assert.Nil(t, data)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		expired := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}, MaxAge: -time.Minute})
+
+		_, err := store.decode(expired.encode([]byte("{}")))
+		require.ErrorIs(t, err, errSessionExpired)
+	})
+
+	t.Run("too large without fallback", func(t *testing.T) {
+		t.Parallel()
+
+		small := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}, MaxCookieSize: 512})
+		small.config.Fallback = nil // as built with -tags=production
+
+		w := httptest.NewRecorder()
+		err := small.Save(w, httptest.NewRequest(http.MethodPost, "/", nil), bytes.Repeat([]byte("a"), 1024))
+		require.ErrorIs(t, err, ErrSessionTooLarge)
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("development fallback", func(t *testing.T) {
+		t.Parallel()
+
+		if !development {
+			t.Skip("the fallback defaults to a MemorySessionStore in development only")
+		}
+
+		small := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}, MaxCookieSize: 512})
+
+		errs := make([]inertia.ValidationError, 50)
+		for i := range errs {
+			errs[i] = inertia.NewValidationError(fmt.Sprintf("field_%d", i), "is required")
+		}
+
+		//nolint:exhaustruct
+		sess := &session{}
+		sess.SetValidationErrors(errs)
+
+		loaded, cookies := roundTrip(t, small, small, sess)
+
+		require.Len(t, cookies, 1)
+		assert.Equal(t, SessionCookieName+"_id", cookies[0].Name)
+		assert.Len(t, loaded.ValidationErrors(), 50)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		t.Parallel()
+
+		fallback := NewMemorySessionStore(&SessionConfig{CookieName: "session_id"})
+		small := NewCookieSessionStore(&SessionConfig{
+			Keys:          [][]byte{newTestKey(1)},
+			MaxCookieSize: 512,
+			Fallback:      fallback,
+		})
+
+		errs := make([]inertia.ValidationError, 50)
+		for i := range errs {
+			errs[i] = inertia.NewValidationError(fmt.Sprintf("field_%d", i), "is required")
+		}
+
+		//nolint:exhaustruct
+		sess := &session{}
+		sess.SetValidationErrors(errs)
+
+		loaded, cookies := roundTrip(t, small, small, sess)
+
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "session_id", cookies[0].Name)
+		assert.Len(t, loaded.ValidationErrors(), 50)
+		assert.Len(t, fallback.entries, 1)
+
+		// Once small enough, the session moves back to the cookie.
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookies[0])
+
+		w := httptest.NewRecorder()
+		require.NoError(t, small.Save(w, r, []byte("{}")))
+
+		assert.Empty(t, fallback.entries)
+
+		names := make([]string, 0, 2)
+		for _, c := range w.Result().Cookies() {
+			names = append(names, c.Name)
+		}
+
+		assert.ElementsMatch(t, []string{SessionCookieName, "session_id"}, names)
+	})
+}
+
+func TestNewSessionAEADs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keys        [][]byte
+		development bool
+		err         bool
+	}{
+		{name: "key", keys: [][]byte{newTestKey(1)}},
+		{name: "rotated keys", keys: [][]byte{newTestKey(1), newTestKey(2)}},
+		{name: "no keys in development", development: true},
+		{name: "no keys in production", err: true},
+		{name: "short key", keys: [][]byte{[]byte("secret")}, development: true, err: true},
+		{name: "short rotated key", keys: [][]byte{newTestKey(1), newTestKey(2)[:16]}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			aeads, err := newSessionAEADs(tt.keys, tt.development)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, aeads, max(len(tt.keys), 1))
+		})
+	}
+
+	t.Run("panics on invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { NewCookieSessionStore(&SessionConfig{Keys: [][]byte{[]byte("secret")}}) })
+	})
+}
+
+func TestDefaultValidationErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errs := make([]inertia.ValidationError, 200)
+	for i := range errs {
+		errs[i] = inertia.NewValidationError(fmt.Sprintf("field_%d", i), "is required")
+	}
+
+	handle := func(t *testing.T, store SessionStore) *httptest.ResponseRecorder {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodPost, "/users", nil)
+		r.Header.Set("Referer", "/users/new")
+
+		w := httptest.NewRecorder()
+		require.NotPanics(t, func() {
+			DefaultValidationErrorHandler(w, withSessionStore(r, store), inertia.ValidationErrors(errs))
+		})
+
+		assert.Equal(t, "/users/new", w.Header().Get("Location"))
+
+		return w
+	}
+
+	t.Run("oversized errors", func(t *testing.T) {
+		t.Parallel()
+
+		if !development {
+			t.Skip("the fallback defaults to a MemorySessionStore in development only")
+		}
+
+		store := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}})
+		w := handle(t, store)
+
+		r := httptest.NewRequest(http.MethodGet, "/users/new", nil)
+		for _, c := range w.Result().Cookies() {
+			r.AddCookie(c)
+		}
+
+		sess, err := sessionFromRequest(withSessionStore(r, store))
+		require.NoError(t, err)
+		assert.Len(t, sess.ValidationErrors(), len(errs))
+	})
+
+	t.Run("oversized errors without fallback", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewCookieSessionStore(&SessionConfig{Keys: [][]byte{newTestKey(1)}})
+		store.config.Fallback = nil // as built with -tags=production
+
+		w := handle(t, store)
+
+		assert.Empty(t, w.Result().Cookies())
+	})
+}
+
+func TestMountSessionStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemorySessionStore(nil)
+
+	tests := []struct {
+		opts        *MountOpts
+		name        string
+		development bool
+		expectStore bool
+		err         bool
+	}{
+		//nolint:exhaustruct
+		{name: "store", opts: &MountOpts{SessionStore: store}, expectStore: true},
+		//nolint:exhaustruct
+		{name: "session", opts: &MountOpts{Session: &SessionConfig{Keys: [][]byte{newTestKey(1)}}}, expectStore: true},
+		//nolint:exhaustruct
+		{name: "invalid key", opts: &MountOpts{Session: &SessionConfig{Keys: [][]byte{[]byte("secret")}}}, err: true},
+		//nolint:exhaustruct
+		{name: "none in development", opts: &MountOpts{}, development: true},
+		//nolint:exhaustruct
+		{name: "none in production", opts: &MountOpts{}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := mountSessionStore(tt.opts, tt.development)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectStore, got != nil)
+		})
+	}
+}
+
+func TestMemorySessionStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemorySessionStore(nil)
+
+	w := httptest.NewRecorder()
+	require.NoError(t, store.Save(w, httptest.NewRequest(http.MethodPost, "/", nil), []byte("a")))
+
+	cookie := w.Result().Cookies()[0]
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+
+	w = httptest.NewRecorder()
+	require.NoError(t, store.Save(w, r, []byte("b")))
+	assert.Equal(t, cookie.Value, w.Result().Cookies()[0].Value, "session ID must be reused")
+
+	data, err := store.Load(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), data)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "unknown"})
+
+	data, err = store.Load(r)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	// Expired entries are not loaded.
+	store.entries[cookie.Value].Value.(*memorySession).expires = time.Now().Add(-time.Second) //nolint:forcetypeassert
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+
+	data, err = store.Load(r)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, store.entries)
+}
+
+func TestMemorySessionStore_Bounds(t *testing.T) {
+	t.Parallel()
+
+	save := func(t *testing.T, store *MemorySessionStore, data []byte) *http.Cookie {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		require.NoError(t, store.Save(w, httptest.NewRequest(http.MethodPost, "/", nil), data))
+
+		return w.Result().Cookies()[0]
+	}
+
+	load := func(t *testing.T, store *MemorySessionStore, cookie *http.Cookie) []byte {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookie)
+
+		data, err := store.Load(r)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	t.Run("max sessions", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemorySessionStore(&SessionConfig{MaxSessions: 2})
+
+		first := save(t, store, []byte("a"))
+		second := save(t, store, []byte("b"))
+		third := save(t, store, []byte("c"))
+
+		assert.Len(t, store.entries, 2)
+		assert.Nil(t, load(t, store, first), "least recently saved session must be evicted")
+		assert.Equal(t, []byte("b"), load(t, store, second))
+		assert.Equal(t, []byte("c"), load(t, store, third))
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemorySessionStore(&SessionConfig{MaxSessionBytes: 10})
+
+		first := save(t, store, []byte("aaaaaa"))
+		second := save(t, store, []byte("bbbbbb"))
+
+		assert.Nil(t, load(t, store, first))
+		assert.Equal(t, []byte("bbbbbb"), load(t, store, second))
+		assert.Equal(t, 6, store.size)
+
+		w := httptest.NewRecorder()
+		err := store.Save(w, httptest.NewRequest(http.MethodPost, "/", nil), bytes.Repeat([]byte("c"), 11))
+		require.ErrorIs(t, err, ErrSessionTooLarge)
+		assert.Equal(t, []byte("bbbbbb"), load(t, store, second))
+	})
+}
+
+type failingSessionStore struct{ err error }
+
+func (s failingSessionStore) Load(*http.Request) ([]byte, error) { return nil, s.err }
+
+func (s failingSessionStore) Save(http.ResponseWriter, *http.Request, []byte) error { return s.err }
+
+func (s failingSessionStore) Clear(http.ResponseWriter, *http.Request) error { return s.err }
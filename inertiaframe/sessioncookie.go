@@ -0,0 +1,382 @@
+package inertiaframe
+
+import (
+	"cmp"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSessionMaxAge is the default lifetime of a session.
+	DefaultSessionMaxAge = 2 * time.Hour
+
+	// DefaultMaxSessionCookieSize is the default maximum size of the
+	// session cookie in bytes, including its name.
+	DefaultMaxSessionCookieSize = 4096
+
+	// DefaultMaxSessions is the default maximum number of sessions kept
+	// by a MemorySessionStore.
+	DefaultMaxSessions = 10_000
+
+	// DefaultMaxSessionBytes is the default maximum total size of sessions
+	// kept by a MemorySessionStore in bytes.
+	DefaultMaxSessionBytes = 32 << 20 // 32 MiB
+
+	// SessionKeySize is the size of a session key in bytes.
+	SessionKeySize = 32
+)
+
+// expiresSize is the size of the expiration time prefixing the
+// encrypted session data.
+const expiresSize = 8
+
+// ErrSessionTooLarge is returned when a session exceeds the capacity
+// of a store, e.g., a CookieSessionStore without a fallback.
+var ErrSessionTooLarge = errors.New("inertiaframe: session too large")
+
+var (
+	errSessionKeysRequired  = errors.New("inertiaframe: session keys are required in production")
+	errSessionStoreRequired = errors.New("inertiaframe: MountOpts.SessionStore or MountOpts.Session " +
+		"is required in production")
+)
+
+var (
+	errSessionMalformed = errors.New("inertiaframe: malformed session cookie")
+	errSessionExpired   = errors.New("inertiaframe: session expired")
+)
+
+// defaultSessionKey is the key used in development when
+// SessionConfig.Keys is empty.
+//
+//nolint:gochecknoglobals
+var defaultSessionKey = sync.OnceValue(func() []byte {
+	slog.Warn("inertiaframe: no session keys configured, using a random key; " +
+		"sessions neither survive restarts nor are shared between instances")
+
+	key := make([]byte, SessionKeySize)
+	_, _ = rand.Read(key) // never returns an error
+
+	return key
+})
+
+// SessionConfig is the configuration of the session stores.
+type SessionConfig struct {
+	// Fallback keeps sessions too large for the cookie of
+	// a CookieSessionStore, e.g., with many validation errors.
+	//
+	// It is optional. When built with -tags=production, saving such
+	// a session without a fallback fails with ErrSessionTooLarge.
+	// Otherwise, it defaults to a MemorySessionStore bounded by MaxSessions
+	// and MaxSessionBytes. In multi-instance deployments, the fallback must
+	// be shared by the instances, e.g., a database store.
+	Fallback SessionStore
+
+	// Keys encrypt and authenticate the cookie of a CookieSessionStore
+	// with AES-256-GCM. Each key must be SessionKeySize bytes long.
+	//
+	// The first key encrypts new cookies, and all keys decrypt. To rotate
+	// keys, prepend a new key and remove the old one once the cookies
+	// encrypted with it have expired.
+	//
+	// Keys are required when built with -tags=production. Otherwise, if
+	// Keys is empty, a random key is generated on startup and a warning is
+	// logged, as sessions neither survive restarts nor are shared between
+	// instances.
+	Keys [][]byte
+
+	// CookieName is the name of the session cookie.
+	//
+	// It defaults to SessionCookieName.
+	CookieName string
+
+	// Path is the path of the session cookie.
+	//
+	// It defaults to SessionPath.
+	Path string
+
+	// Domain is the domain of the session cookie.
+	//
+	// It is optional.
+	Domain string
+
+	// MaxAge is the lifetime of a session.
+	//
+	// It defaults to DefaultSessionMaxAge.
+	MaxAge time.Duration
+
+	// MaxCookieSize is the maximum size of the cookie of
+	// a CookieSessionStore in bytes. A larger session is saved to Fallback.
+	//
+	// It defaults to DefaultMaxSessionCookieSize.
+	MaxCookieSize int
+
+	// MaxSessions is the maximum number of sessions kept by
+	// a MemorySessionStore.
+	//
+	// It defaults to DefaultMaxSessions.
+	MaxSessions int
+
+	// MaxSessionBytes is the maximum total size of sessions kept by
+	// a MemorySessionStore in bytes.
+	//
+	// It defaults to DefaultMaxSessionBytes.
+	MaxSessionBytes int
+
+	// SameSite is the SameSite attribute of the session cookie.
+	//
+	// It defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// Secure sets the Secure attribute of the session cookie.
+	Secure bool
+}
+
+// cookieDefaults sets the default values of the cookie attributes.
+func (c *SessionConfig) cookieDefaults() {
+	c.CookieName = cmp.Or(c.CookieName, SessionCookieName)
+	c.Path = cmp.Or(c.Path, SessionPath)
+	c.MaxAge = cmp.Or(c.MaxAge, DefaultSessionMaxAge)
+	c.SameSite = cmp.Or(c.SameSite, http.SameSiteLaxMode)
+}
+
+func (c *SessionConfig) defaults() {
+	c.cookieDefaults()
+	c.MaxCookieSize = cmp.Or(c.MaxCookieSize, DefaultMaxSessionCookieSize)
+}
+
+// cookie returns the session cookie with the value.
+//
+// A negative maxAge deletes the cookie.
+func (c *SessionConfig) cookie(value string, maxAge int) *http.Cookie {
+	//nolint:exhaustruct
+	return &http.Cookie{
+		Name:     c.CookieName,
+		Value:    value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   c.Secure,
+		SameSite: c.SameSite,
+	}
+}
+
+// CookieSessionStore is a SessionStore keeping sessions in an encrypted
+// cookie.
+//
+// A session exceeding SessionConfig.MaxCookieSize is saved to
+// SessionConfig.Fallback instead, if any.
+//
+// To create a new CookieSessionStore, use the NewCookieSessionStore function.
+type CookieSessionStore struct {
+	aeads  []cipher.AEAD
+	config SessionConfig
+}
+
+// NewCookieSessionStore creates a new CookieSessionStore. If config is nil,
+// the default configuration is used.
+//
+// It panics if SessionConfig.Keys is invalid, see SessionConfig.Keys.
+func NewCookieSessionStore(config *SessionConfig) *CookieSessionStore {
+	store, err := newCookieSessionStore(config)
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+// newCookieSessionStore is like NewCookieSessionStore, but it returns
+// an error if SessionConfig.Keys is invalid.
+func newCookieSessionStore(config *SessionConfig) (*CookieSessionStore, error) {
+	//nolint:exhaustruct
+	c := SessionConfig{}
+	if config != nil {
+		c = *config
+	}
+
+	c.defaults()
+
+	aeads, err := newSessionAEADs(c.Keys, development)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Fallback == nil && development {
+		c.Fallback = newDevelopmentFallback(&c)
+	}
+
+	return &CookieSessionStore{aeads: aeads, config: c}, nil
+}
+
+// newDevelopmentFallback creates the MemorySessionStore used in development
+// as the fallback of a CookieSessionStore without SessionConfig.Fallback.
+func newDevelopmentFallback(c *SessionConfig) *MemorySessionStore {
+	//nolint:exhaustruct
+	return NewMemorySessionStore(&SessionConfig{
+		CookieName:      c.CookieName + "_id",
+		Path:            c.Path,
+		Domain:          c.Domain,
+		MaxAge:          c.MaxAge,
+		MaxSessions:     c.MaxSessions,
+		MaxSessionBytes: c.MaxSessionBytes,
+		SameSite:        c.SameSite,
+		Secure:          c.Secure,
+	})
+}
+
+// newSessionAEADs creates the ciphers for the session keys.
+//
+// In development, a random key is used if keys is empty.
+func newSessionAEADs(keys [][]byte, development bool) ([]cipher.AEAD, error) {
+	if len(keys) == 0 {
+		if !development {
+			return nil, errSessionKeysRequired
+		}
+
+		keys = [][]byte{defaultSessionKey()}
+	}
+
+	aeads := make([]cipher.AEAD, 0, len(keys))
+	for i, key := range keys {
+		if len(key) != SessionKeySize {
+			return nil, fmt.Errorf("inertiaframe: session key %d must be %d bytes long, got %d",
+				i, SessionKeySize, len(key))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("inertiaframe: invalid session key %d: %w", i, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("inertiaframe: failed to create session cipher: %w", err)
+		}
+
+		aeads = append(aeads, aead)
+	}
+
+	return aeads, nil
+}
+
+// Load implements SessionStore.
+//
+// A cookie that is malformed, expired or encrypted with an unknown key
+// is ignored. Without a valid cookie, the session is loaded from
+// SessionConfig.Fallback.
+func (s *CookieSessionStore) Load(r *http.Request) ([]byte, error) {
+	if c, err := r.Cookie(s.config.CookieName); err == nil {
+		data, err := s.decode(c.Value)
+		if err == nil {
+			return data, nil
+		}
+
+		d("ignoring session cookie: %v", err)
+	}
+
+	if s.config.Fallback == nil {
+		return nil, nil
+	}
+
+	//nolint:wrapcheck
+	return s.config.Fallback.Load(r)
+}
+
+// Save implements SessionStore.
+func (s *CookieSessionStore) Save(w http.ResponseWriter, r *http.Request, data []byte) error {
+	value := s.encode(data)
+	if len(s.config.CookieName)+len(value) <= s.config.MaxCookieSize {
+		http.SetCookie(w, s.config.cookie(value, int(s.config.MaxAge.Seconds())))
+
+		if s.config.Fallback == nil {
+			return nil
+		}
+
+		//nolint:wrapcheck
+		return s.config.Fallback.Clear(w, r)
+	}
+
+	if s.config.Fallback == nil {
+		return ErrSessionTooLarge
+	}
+
+	d("session cookie exceeds %d bytes, saving the session to the fallback store", s.config.MaxCookieSize)
+
+	if _, err := r.Cookie(s.config.CookieName); err == nil {
+		http.SetCookie(w, s.config.cookie("", -1))
+	}
+
+	//nolint:wrapcheck
+	return s.config.Fallback.Save(w, r, data)
+}
+
+// Clear implements SessionStore.
+func (s *CookieSessionStore) Clear(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, s.config.cookie("", -1))
+
+	if s.config.Fallback == nil {
+		return nil
+	}
+
+	//nolint:wrapcheck
+	return s.config.Fallback.Clear(w, r)
+}
+
+// encode encrypts the data prefixed with its expiration time with
+// the first key.
+func (s *CookieSessionStore) encode(data []byte) string {
+	aead := s.aeads[0]
+
+	b := make([]byte, expiresSize, expiresSize+len(data))
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Add(s.config.MaxAge).Unix())) //nolint:gosec
+	b = append(b, data...)
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(b)+aead.Overhead())
+	_, _ = rand.Read(nonce) // never returns an error
+
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, b, []byte(s.config.CookieName)))
+}
+
+// decode decrypts the value with any of the keys.
+func (s *CookieSessionStore) decode(value string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errSessionMalformed
+	}
+
+	for _, aead := range s.aeads {
+		if len(b) < aead.NonceSize() {
+			continue
+		}
+
+		nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+
+		plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(s.config.CookieName))
+		if err != nil {
+			continue
+		}
+
+		if len(plaintext) < expiresSize {
+			return nil, errSessionMalformed
+		}
+
+		expires := time.Unix(int64(binary.BigEndian.Uint64(plaintext)), 0) //nolint:gosec
+		if !time.Now().Before(expires) {
+			return nil, errSessionExpired
+		}
+
+		return plaintext[expiresSize:], nil
+	}
+
+	return nil, errSessionMalformed
+}
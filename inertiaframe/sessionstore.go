@@ -0,0 +1,251 @@
+package inertiaframe
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go.inout.gg/foundations/http/httpmiddleware"
+)
+
+var (
+	_ SessionStore = (*CookieSessionStore)(nil)
+	_ SessionStore = (*MemorySessionStore)(nil)
+)
+
+// sessionIDSize is the number of random bytes of a session ID.
+const sessionIDSize = 24
+
+type sessStoreCtx struct{}
+
+var kSessStoreCtx = sessStoreCtx{} //nolint:gochecknoglobals
+
+// defaultSessionStore is the store used when none is attached
+// to the request. As it has no keys, it returns errSessionKeysRequired
+// when built with -tags=production.
+//
+//nolint:gochecknoglobals
+var defaultSessionStore = sync.OnceValues(func() (SessionStore, error) {
+	store, err := newCookieSessionStore(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+})
+
+// SessionStore loads and saves the inertiaframe session of a request.
+//
+// The session carries validation errors across redirects and the last
+// visited path used by RedirectBack. It is encoded by inertiaframe, so
+// a store only keeps opaque data, e.g., in a database table or in an
+// application session.
+//
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Load returns the session data of the request.
+	//
+	// If there is no session, it returns nil data and a nil error.
+	Load(r *http.Request) ([]byte, error)
+
+	// Save saves the session data of the request.
+	Save(w http.ResponseWriter, r *http.Request, data []byte) error
+
+	// Clear removes the session of the request.
+	Clear(w http.ResponseWriter, r *http.Request) error
+}
+
+// SessionMiddleware attaches the store to requests.
+//
+// When built with -tags=production, a store must be attached to requests
+// using sessions, either with SessionMiddleware or MountOpts. Otherwise,
+// loading the session fails, as the default store has no keys.
+//
+// The store is used by RedirectBack, DefaultValidationErrorHandler and
+// mounted endpoints without MountOpts.SessionStore and MountOpts.Session,
+// including handlers not mounted with Mount.
+func SessionMiddleware(store SessionStore) httpmiddleware.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, withSessionStore(r, store))
+		})
+	}
+}
+
+// withSessionStore returns a copy of r using the store to load and save
+// the session.
+func withSessionStore(r *http.Request, store SessionStore) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), kSessStoreCtx, store))
+}
+
+// sessionStoreFromRequest returns the store attached to the request,
+// or the default cookie store.
+func sessionStoreFromRequest(r *http.Request) (SessionStore, error) {
+	if store, ok := r.Context().Value(kSessStoreCtx).(SessionStore); ok {
+		return store, nil
+	}
+
+	return defaultSessionStore()
+}
+
+// MemorySessionStore is a SessionStore keeping sessions in the memory of
+// the process. The session ID is sent in a cookie.
+//
+// Sessions are not shared between instances and are lost on restart.
+// It is meant for development, tests and as a fallback for sessions
+// too large for a CookieSessionStore in single-instance deployments.
+//
+// The store is bounded by SessionConfig.MaxSessions and MaxSessionBytes,
+// the least recently saved sessions are evicted first.
+//
+// To create a new MemorySessionStore, use the NewMemorySessionStore function.
+type MemorySessionStore struct {
+	entries map[string]*list.Element
+	order   *list.List // of *memorySession, least recently saved first
+	config  SessionConfig
+	size    int
+	mu      sync.Mutex
+}
+
+type memorySession struct {
+	expires time.Time
+	id      string
+	data    []byte
+}
+
+// NewMemorySessionStore creates a new MemorySessionStore. If config is nil,
+// the default configuration is used. SessionConfig.Keys, MaxCookieSize and
+// Fallback are ignored.
+func NewMemorySessionStore(config *SessionConfig) *MemorySessionStore {
+	//nolint:exhaustruct
+	c := SessionConfig{}
+	if config != nil {
+		c = *config
+	}
+
+	c.cookieDefaults()
+	c.MaxSessions = cmp.Or(c.MaxSessions, DefaultMaxSessions)
+	c.MaxSessionBytes = cmp.Or(c.MaxSessionBytes, DefaultMaxSessionBytes)
+
+	return &MemorySessionStore{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		config:  c,
+		size:    0,
+		mu:      sync.Mutex{},
+	}
+}
+
+// Load implements SessionStore.
+func (s *MemorySessionStore) Load(r *http.Request) ([]byte, error) {
+	c, err := r.Cookie(s.config.CookieName)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[c.Value]
+	if !ok {
+		return nil, nil
+	}
+
+	e := el.Value.(*memorySession) //nolint:forcetypeassert
+	if time.Now().After(e.expires) {
+		s.remove(el)
+		return nil, nil
+	}
+
+	return slices.Clone(e.data), nil
+}
+
+// Save implements SessionStore.
+//
+// The session ID of the request is reused if the session exists,
+// otherwise a new ID is issued. If data exceeds
+// SessionConfig.MaxSessionBytes, ErrSessionTooLarge is returned.
+func (s *MemorySessionStore) Save(w http.ResponseWriter, r *http.Request, data []byte) error {
+	if len(data) > s.config.MaxSessionBytes {
+		return ErrSessionTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := ""
+	if c, err := r.Cookie(s.config.CookieName); err == nil {
+		if el, ok := s.entries[c.Value]; ok {
+			id = c.Value
+			s.remove(el)
+		}
+	}
+
+	if id == "" {
+		id = newSessionID()
+	}
+
+	now := time.Now()
+	s.evict(now, len(data))
+
+	s.entries[id] = s.order.PushBack(&memorySession{expires: now.Add(s.config.MaxAge), id: id, data: slices.Clone(data)})
+	s.size += len(data)
+
+	http.SetCookie(w, s.config.cookie(id, int(s.config.MaxAge.Seconds())))
+
+	return nil
+}
+
+// Clear implements SessionStore.
+func (s *MemorySessionStore) Clear(w http.ResponseWriter, r *http.Request) error {
+	c, err := r.Cookie(s.config.CookieName)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	s.mu.Lock()
+	if el, ok := s.entries[c.Value]; ok {
+		s.remove(el)
+	}
+	s.mu.Unlock()
+
+	http.SetCookie(w, s.config.cookie("", -1))
+
+	return nil
+}
+
+// evict removes the expired sessions and the least recently saved ones
+// until a session of size bytes fits.
+//
+// Sessions share the same lifetime, so the expired ones are at the front.
+func (s *MemorySessionStore) evict(now time.Time, size int) {
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		e := el.Value.(*memorySession) //nolint:forcetypeassert
+		if !now.After(e.expires) &&
+			s.order.Len() < s.config.MaxSessions &&
+			s.size+size <= s.config.MaxSessionBytes {
+			return
+		}
+
+		s.remove(el)
+	}
+}
+
+func (s *MemorySessionStore) remove(el *list.Element) {
+	e := s.order.Remove(el).(*memorySession) //nolint:forcetypeassert
+	delete(s.entries, e.id)
+	s.size -= len(e.data)
+}
+
+func newSessionID() string {
+	b := make([]byte, sessionIDSize)
+	_, _ = rand.Read(b) // never returns an error
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
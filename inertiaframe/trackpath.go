@@ -0,0 +1,152 @@
+package inertiaframe
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.inout.gg/foundations/http/httpmiddleware"
+
+	"go.inout.gg/inertia"
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+// MaxTrackedURLLength is the maximum length of a URL recorded by
+// TrackPathMiddleware. Longer URLs are not recorded.
+const MaxTrackedURLLength = 1024
+
+var (
+	_ http.ResponseWriter                       = (*trackPathWriter)(nil)
+	_ interface{ Unwrap() http.ResponseWriter } = (*trackPathWriter)(nil)
+)
+
+// TrackPathMiddleware records the URL of the last successfully rendered
+// page in the session, so RedirectBack works when the Referer header is
+// stripped, e.g., by the browser or a privacy extension.
+//
+// Only GET requests for pages answered with 200 OK are recorded:
+// Inertia.js partial reloads, prefetches and requests not accepting HTML,
+// e.g., assets, are ignored. So are URLs longer than MaxTrackedURLLength,
+// as the query string is controlled by the client.
+//
+// The session is loaded from the store attached by SessionMiddleware,
+// if it is placed before TrackPathMiddleware. Otherwise, it is loaded
+// from the store of the mounted endpoint handling the request.
+// Place it after inertia.Middleware to record the URL resolved by
+// the inertia.Renderer.
+func TrackPathMiddleware() httpmiddleware.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isTrackedRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), kSessRefCtx, &sessionRef{sess: nil}))
+			tw := &trackPathWriter{ResponseWriter: w, r: r, written: false}
+
+			next.ServeHTTP(tw, r)
+		})
+	}
+}
+
+// isTrackedRequest reports whether the request is a page visit.
+func isTrackedRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+
+	h := r.Header
+	if h.Get(inertiaheader.HeaderXInertiaPartialComponent) != "" {
+		return false
+	}
+
+	if strings.Contains(h.Get(inertiaheader.HeaderPurpose), "prefetch") ||
+		strings.Contains(h.Get(inertiaheader.HeaderSecPurpose), "prefetch") {
+		return false
+	}
+
+	if h.Get(inertiaheader.HeaderXInertia) == "true" {
+		return true
+	}
+
+	return acceptsHTML(h.Get(inertiaheader.HeaderAccept))
+}
+
+// acceptsHTML reports whether the Accept header lists text/html.
+func acceptsHTML(accept string) bool {
+	for v := range strings.SplitSeq(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err == nil && mediaType == "text/html" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trackPathWriter is a wrapper around http.ResponseWriter saving
+// the request URL to the session before a 200 OK response is written.
+type trackPathWriter struct {
+	http.ResponseWriter
+
+	r       *http.Request
+	written bool
+}
+
+func (w *trackPathWriter) WriteHeader(code int) {
+	if !w.written {
+		w.written = true
+
+		if code == http.StatusOK {
+			w.track()
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *trackPathWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+func (w *trackPathWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// track saves the request URL to the session.
+func (w *trackPathWriter) track() {
+	r := w.r
+	ctx := r.Context()
+
+	path := inertia.RequestURL(r)
+	if len(path) > MaxTrackedURLLength {
+		d("not tracking URL longer than %d bytes", MaxTrackedURLLength)
+		return
+	}
+
+	sess, err := sessionFromRequest(r)
+	if err != nil {
+		d("failed to load session: %v", err)
+		return
+	}
+
+	if sess.Path_ == path {
+		return
+	}
+
+	sess.Path_ = path
+
+	if err := sess.Save(w.ResponseWriter, r); err != nil {
+		d("failed to save session: %v", err)
+
+		inertia.LoggerFromRequest(r).LogAttrs(ctx, slog.LevelWarn, "inertiaframe: failed to track path",
+			slog.String("path", path),
+			slog.Any("error", err),
+		)
+	}
+}
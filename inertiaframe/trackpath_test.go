@@ -0,0 +1,187 @@
+package inertiaframe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia"
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+func TestTrackPathMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) })
+
+	tests := []struct {
+		handler http.Handler
+		header  map[string]string
+		name    string
+		method  string
+		tracked bool
+	}{
+		{
+			name:    "page visit",
+			method:  http.MethodGet,
+			header:  map[string]string{inertiaheader.HeaderAccept: "text/html,application/xhtml+xml;q=0.9"},
+			handler: ok,
+			tracked: true,
+		},
+		{
+			name:    "inertia visit",
+			method:  http.MethodGet,
+			header:  map[string]string{inertiaheader.HeaderXInertia: "true"},
+			handler: ok,
+			tracked: true,
+		},
+		{
+			name:   "partial reload",
+			method: http.MethodGet,
+			header: map[string]string{
+				inertiaheader.HeaderXInertia:                 "true",
+				inertiaheader.HeaderXInertiaPartialComponent: "Users",
+			},
+			handler: ok,
+		},
+		{
+			name:    "prefetch",
+			method:  http.MethodGet,
+			header:  map[string]string{inertiaheader.HeaderXInertia: "true", inertiaheader.HeaderPurpose: "prefetch"},
+			handler: ok,
+		},
+		{
+			name:    "browser prefetch",
+			method:  http.MethodGet,
+			header:  map[string]string{inertiaheader.HeaderAccept: "text/html", inertiaheader.HeaderSecPurpose: "prefetch"},
+			handler: ok,
+		},
+		{
+			name:    "asset",
+			method:  http.MethodGet,
+			header:  map[string]string{inertiaheader.HeaderAccept: "image/avif,image/webp,*/*"},
+			handler: ok,
+		},
+		{
+			name:    "form submission",
+			method:  http.MethodPost,
+			header:  map[string]string{inertiaheader.HeaderXInertia: "true"},
+			handler: ok,
+		},
+		{
+			name:   "not found",
+			method: http.MethodGet,
+			header: map[string]string{inertiaheader.HeaderXInertia: "true"},
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			}),
+		},
+		{
+			name:   "redirect",
+			method: http.MethodGet,
+			header: map[string]string{inertiaheader.HeaderXInertia: "true"},
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				inertia.Redirect(w, r, "/login")
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewMemorySessionStore(nil)
+			h := SessionMiddleware(store)(TrackPathMiddleware()(tt.handler))
+
+			r := httptest.NewRequest(tt.method, "/users?page=2", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			cookies := w.Result().Cookies()
+			if !tt.tracked {
+				assert.Empty(t, cookies)
+				return
+			}
+
+			require.Len(t, cookies, 1)
+
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(cookies[0])
+
+			sess, err := sessionFromRequest(withSessionStore(r, store))
+			require.NoError(t, err)
+			assert.Equal(t, "/users?page=2", sess.Referer())
+		})
+	}
+}
+
+func TestTrackPathMiddleware_LongURL(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemorySessionStore(nil)
+	h := SessionMiddleware(store)(TrackPathMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})))
+
+	for range 10 {
+		r := httptest.NewRequest(http.MethodGet, "/?q="+strings.Repeat("a", 100*1024), nil)
+		r.Header.Set(inertiaheader.HeaderAccept, "text/html")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	}
+
+	assert.Empty(t, store.entries, "long URLs must not be stored")
+}
+
+func TestTrackPathMiddleware_SharedSession(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemorySessionStore(nil)
+
+	// The handler consumes the validation errors with a derived request,
+	// as mounted endpoints do.
+	h := TrackPathMiddleware()(SessionMiddleware(store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, err := sessionFromRequest(r)
+		require.NoError(t, err)
+
+		assert.Len(t, sess.ValidationErrors(), 1)
+		require.NoError(t, sess.Save(w, r))
+
+		_, _ = w.Write([]byte("ok"))
+	})))
+
+	//nolint:exhaustruct
+	sess := &session{store: store}
+	sess.SetValidationErrors([]inertia.ValidationError{inertia.NewValidationError("email", "is required")})
+
+	w := httptest.NewRecorder()
+	require.NoError(t, sess.Save(w, httptest.NewRequest(http.MethodPost, "/", nil)))
+
+	cookie := w.Result().Cookies()[0]
+
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set(inertiaheader.HeaderXInertia, "true")
+	r.AddCookie(cookie)
+
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+
+	loaded, err := sessionFromRequest(withSessionStore(r, store))
+	require.NoError(t, err)
+	assert.Equal(t, "/users", loaded.Referer())
+	assert.Empty(t, loaded.ValidationErrors(), "consumed validation errors must not be restored")
+}
@@ -0,0 +1,224 @@
+// Package inertiaprerender implements static site generation for Inertia.js
+// pages.
+//
+// Pages are rendered in-process by running requests through the provided
+// http.Handler, typically an application mux wrapped by inertia.Middleware.
+// For each URL an HTML document and an Inertia.js JSON payload are written
+// to the output directory, so the pages can be served from a CDN without
+// running the Go server.
+//
+// If the Renderer is configured with an SsrClient, the HTML documents
+// are server-side rendered as well.
+package inertiaprerender
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.inout.gg/foundations/debug"
+
+	"go.inout.gg/inertia/inertiaframe"
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+var _ inertiaframe.Mux = (*Mux)(nil)
+
+var d = debug.Debuglog("inertiaprerender") //nolint:gochecknoglobals
+
+const (
+	// HTMLFileName is the name of the file the HTML document is written to.
+	HTMLFileName = "index.html"
+
+	// PageFileName is the name of the file the Inertia.js JSON page payload
+	// is written to.
+	PageFileName = "index.json"
+
+	// DefaultHost is the default host used for prerender requests.
+	DefaultHost = "localhost"
+)
+
+// Config is the configuration for Prerender.
+type Config struct {
+	// Version is the Inertia.js asset version sent with Inertia.js requests.
+	//
+	// It must match the Renderer version, otherwise the middleware responds
+	// with a version mismatch.
+	Version string
+
+	// Host is the host used for prerender requests.
+	//
+	// It defaults to DefaultHost.
+	Host string
+}
+
+func (c *Config) defaults() {
+	c.Host = cmp.Or(c.Host, DefaultHost)
+}
+
+// Prerender renders each of urls through h and writes the results into outDir.
+//
+// For every URL path, e.g. "/about", an HTML document is written to
+// "<outDir>/about/index.html" and the Inertia.js JSON page payload to
+// "<outDir>/about/index.json".
+//
+// Only paths are allowed, URLs with a query string are rejected.
+// Any response with a status code other than 200 OK is treated as an error.
+//
+// If config is nil, the default configuration is used.
+func Prerender(ctx context.Context, h http.Handler, outDir string, urls []string, config *Config) error {
+	debug.Assert(h != nil, "expected h to be defined")
+	debug.Assert(outDir != "", "expected outDir to be defined")
+
+	if config == nil {
+		//nolint:exhaustruct
+		config = &Config{}
+	}
+
+	config.defaults()
+
+	for _, rawURL := range urls {
+		if err := prerender(ctx, h, outDir, rawURL, config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prerender renders a single rawURL and writes its HTML document and
+// JSON page payload into outDir.
+func prerender(ctx context.Context, h http.Handler, outDir string, rawURL string, config *Config) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("inertiaprerender: failed to parse URL %q: %w", rawURL, err)
+	}
+
+	if u.RawQuery != "" {
+		return fmt.Errorf("inertiaprerender: URL %q must not contain a query string", rawURL)
+	}
+
+	p := path.Clean("/" + u.Path)
+	dir := filepath.Join(outDir, filepath.FromSlash(p))
+
+	d("Prerendering %s into %s", p, dir)
+
+	html, err := serve(ctx, h, p, config, false)
+	if err != nil {
+		return err
+	}
+
+	page, err := serve(ctx, h, p, config, true)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("inertiaprerender: failed to create directory %s: %w", dir, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, HTMLFileName), html, 0o644); err != nil { //nolint:gosec,mnd
+		return fmt.Errorf("inertiaprerender: failed to write HTML for %s: %w", p, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, PageFileName), page, 0o644); err != nil { //nolint:gosec,mnd
+		return fmt.Errorf("inertiaprerender: failed to write page for %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// serve runs a GET request for p through h and returns the response body.
+//
+// If inertia is true, the request is sent as an Inertia.js request.
+func serve(ctx context.Context, h http.Handler, p string, config *Config, inertia bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+config.Host+p, nil)
+	if err != nil {
+		return nil, fmt.Errorf("inertiaprerender: failed to create request for %s: %w", p, err)
+	}
+
+	// Client requests don't have RequestURI set, while the renderer relies on it.
+	req.RequestURI = p
+
+	if inertia {
+		req.Header.Set(inertiaheader.HeaderXInertia, "true")
+		req.Header.Set(inertiaheader.HeaderXInertiaVersion, config.Version)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		return nil, fmt.Errorf("inertiaprerender: unexpected HTTP status code for %s: %d", p, w.Code)
+	}
+
+	return w.Body.Bytes(), nil
+}
+
+// Mux is a router that records paths of GET endpoints without path
+// parameters while delegating the handling to the underlying mux.
+//
+// Mux is compatible with the inertiaframe.Mux interface, so endpoints
+// mounted through inertiaframe.Mount can be enumerated for prerendering.
+type Mux struct {
+	mux   inertiaframe.Mux
+	paths []string
+	mu    sync.Mutex
+}
+
+// NewMux creates a new Mux wrapping mux.
+func NewMux(mux inertiaframe.Mux) *Mux {
+	debug.Assert(mux != nil, "expected mux to be defined")
+
+	return &Mux{mux: mux, paths: nil, mu: sync.Mutex{}}
+}
+
+// Handle records the pattern path if it can be prerendered and
+// registers h on the underlying mux.
+func (m *Mux) Handle(pattern string, h http.Handler) {
+	if p, ok := prerenderablePath(pattern); ok {
+		m.mu.Lock()
+		if !slices.Contains(m.paths, p) {
+			m.paths = append(m.paths, p)
+		}
+		m.mu.Unlock()
+	}
+
+	m.mux.Handle(pattern, h)
+}
+
+// URLs returns the recorded paths in the registration order.
+func (m *Mux) URLs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.paths)
+}
+
+// prerenderablePath returns the path of pattern if it is a GET pattern
+// without path parameters or wildcards.
+//
+// The pattern follows the http.ServeMux format: "[<method> ][<host>]<path>".
+func prerenderablePath(pattern string) (string, bool) {
+	method, rest, ok := strings.Cut(pattern, " ")
+	if !ok || method != http.MethodGet {
+		return "", false
+	}
+
+	// "{$}" only anchors the pattern to match the exact path.
+	rest = strings.TrimSuffix(strings.TrimSpace(rest), "{$}")
+	if !strings.HasPrefix(rest, "/") || strings.ContainsAny(rest, "{}*") {
+		return "", false
+	}
+
+	return rest, true
+}
@@ -0,0 +1,109 @@
+package inertiaprerender
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia"
+)
+
+//nolint:gochecknoglobals
+var tpl = template.Must(template.New("test").Parse(`<html><body>{{ .InertiaBody }}</body></html>`))
+
+func newHandler(t *testing.T) (http.Handler, *Mux) {
+	t.Helper()
+
+	renderer := inertia.New(tpl, &inertia.Config{Version: "1.0.0"})
+	serveMux := http.NewServeMux()
+	mux := NewMux(serveMux)
+
+	mux.Handle("GET /{$}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inertia.MustRender(w, r, "Home", inertia.NewRenderContext())
+	}))
+	mux.Handle("GET /about", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inertia.MustRender(w, r, "About", inertia.NewRenderContext(
+			inertia.WithProps(inertia.NewProp("title", "About us", nil)),
+		))
+	}))
+	mux.Handle("GET /users/{id}", http.NotFoundHandler())
+	mux.Handle("POST /contact", http.NotFoundHandler())
+
+	return inertia.Middleware(renderer)(serveMux), mux
+}
+
+func TestPrerender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writes html and page payload", func(t *testing.T) {
+		t.Parallel()
+
+		h, _ := newHandler(t)
+		dir := t.TempDir()
+
+		err := Prerender(t.Context(), h, dir, []string{"/about"}, &Config{Version: "1.0.0"})
+		require.NoError(t, err)
+
+		html, err := os.ReadFile(filepath.Join(dir, "about", HTMLFileName))
+		require.NoError(t, err)
+		assert.Contains(t, string(html), `<div id="app" data-page="`)
+
+		b, err := os.ReadFile(filepath.Join(dir, "about", PageFileName))
+		require.NoError(t, err)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(b, &page))
+		assert.Equal(t, "About", page.Component)
+		assert.Equal(t, "/about", page.URL)
+		assert.Equal(t, "About us", page.Props["title"])
+	})
+
+	t.Run("fails on version mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		h, _ := newHandler(t)
+
+		err := Prerender(t.Context(), h, t.TempDir(), []string{"/about"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("fails on unexpected status code", func(t *testing.T) {
+		t.Parallel()
+
+		h, _ := newHandler(t)
+
+		err := Prerender(t.Context(), h, t.TempDir(), []string{"/missing"}, &Config{Version: "1.0.0"})
+		require.Error(t, err)
+	})
+
+	t.Run("rejects query string", func(t *testing.T) {
+		t.Parallel()
+
+		h, _ := newHandler(t)
+
+		err := Prerender(t.Context(), h, t.TempDir(), []string{"/about?tab=1"}, &Config{Version: "1.0.0"})
+		require.Error(t, err)
+	})
+}
+
+func TestMux_URLs(t *testing.T) {
+	t.Parallel()
+
+	h, mux := newHandler(t)
+	assert.Equal(t, []string{"/", "/about"}, mux.URLs())
+
+	dir := t.TempDir()
+	err := Prerender(t.Context(), h, dir, mux.URLs(), &Config{Version: "1.0.0"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dir, HTMLFileName))
+	assert.FileExists(t, filepath.Join(dir, PageFileName))
+	assert.FileExists(t, filepath.Join(dir, "about", HTMLFileName))
+	assert.FileExists(t, filepath.Join(dir, "about", PageFileName))
+}
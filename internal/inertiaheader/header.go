@@ -9,8 +9,20 @@ const (
 	HeaderXInertiaPartialComponent = "X-Inertia-Partial-Component" // client
 	HeaderXInertiaReset            = "X-Inertia-Reset"             // client, force reload
 	HeaderXInertiaErrorBag         = "X-Inertia-Error-Bag"         // client
+	HeaderXInertiaNonce            = "X-Inertia-Nonce"             // server, SSR CSP nonce
 
-	HeaderVary        = "Vary"
-	HeaderContentType = "Content-Type"
-	HeaderReferer     = "Referer"
+	HeaderVary         = "Vary"
+	HeaderAccept       = "Accept"
+	HeaderPurpose      = "Purpose"
+	HeaderSecPurpose   = "Sec-Purpose"
+	HeaderContentType  = "Content-Type"
+	HeaderReferer      = "Referer"
+	HeaderETag         = "ETag"
+	HeaderIfNoneMatch  = "If-None-Match"
+	HeaderServerTiming = "Server-Timing"
+	HeaderXXSRFToken   = "X-XSRF-TOKEN"
+
+	HeaderXForwardedHost   = "X-Forwarded-Host"
+	HeaderXForwardedProto  = "X-Forwarded-Proto"
+	HeaderXForwardedPrefix = "X-Forwarded-Prefix"
 )
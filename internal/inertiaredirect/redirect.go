@@ -1,6 +1,7 @@
 package inertiaredirect
 
 import (
+	"log/slog"
 	"net/http"
 
 	"go.inout.gg/foundations/debug"
@@ -9,7 +10,7 @@ import (
 //nolint:gochecknoglobals
 var d = debug.Debuglog("inertia/redirect")
 
-func Redirect(w http.ResponseWriter, r *http.Request, url string) {
+func Redirect(w http.ResponseWriter, r *http.Request, url string, logger *slog.Logger) {
 	// Redirect GET requests with a 302
 	statusCode := http.StatusSeeOther
 	if r.Method == http.MethodGet {
@@ -18,5 +19,10 @@ func Redirect(w http.ResponseWriter, r *http.Request, url string) {
 
 	d("Redirecting to %s with status code %d", url, statusCode)
 
+	logger.LogAttrs(r.Context(), slog.LevelDebug, "inertia: redirecting",
+		slog.String("location", url),
+		slog.Int("status", statusCode),
+	)
+
 	http.Redirect(w, r, url, statusCode)
 }
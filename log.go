@@ -0,0 +1,62 @@
+package inertia
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+// DiscardLogger is a logger that discards all records.
+//
+// It is used by default when no logger is configured.
+//
+//nolint:gochecknoglobals
+var DiscardLogger = slog.New(slog.DiscardHandler)
+
+// Logger returns the logger of the renderer.
+func (r *Renderer) Logger() *slog.Logger { return r.logger }
+
+// LoggerFromRequest returns the logger of the renderer attached to the
+// request by the Middleware.
+//
+// If there is no renderer, DiscardLogger is returned.
+func LoggerFromRequest(req *http.Request) *slog.Logger {
+	renderer, ok := req.Context().Value(kCtxKey).(*Renderer)
+	if !ok {
+		return DiscardLogger
+	}
+
+	return renderer.logger
+}
+
+// requestAttrs returns log attributes describing the Inertia.js request
+// rendering the component.
+func requestAttrs(req *http.Request, componentName string) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("component", componentName),
+		slog.Bool("inertia", isInertiaRequest(req)),
+	}
+
+	if v := req.Header.Get(inertiaheader.HeaderXInertiaVersion); v != "" {
+		attrs = append(attrs, slog.String("version", v))
+	}
+
+	if isPartialComponentRequest(req, componentName) {
+		attrs = append(attrs, slog.Bool("partial", true))
+
+		if v := req.Header.Get(inertiaheader.HeaderXInertiaPartialData); v != "" {
+			attrs = append(attrs, slog.Any("partial_data", extractHeaderValueList(v)))
+		}
+
+		if v := req.Header.Get(inertiaheader.HeaderXInertiaPartialExcept); v != "" {
+			attrs = append(attrs, slog.Any("partial_except", extractHeaderValueList(v)))
+		}
+	}
+
+	if v := req.Header.Get(inertiaheader.HeaderXInertiaErrorBag); v != "" {
+		attrs = append(attrs, slog.String("error_bag", v))
+	}
+
+	return attrs
+}
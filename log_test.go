@@ -0,0 +1,120 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+// logRecords decodes JSON log records written by slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record map[string]any
+		require.NoError(t, dec.Decode(&record))
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	//nolint:exhaustruct
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestRenderer_Logger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("partial reload", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		renderer := New(testTpl, &Config{Version: "1", Logger: newTestLogger(&buf)})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			Version:          "1",
+			PartialComponent: "Users",
+			Whitelist:        []string{"users", "filters"},
+		})
+		req.Header.Set(inertiaheader.HeaderXInertiaErrorBag, "createUser")
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(
+			WithProps(Props{
+				NewProp("users", []string{"alice"}, nil),
+				NewProp("filters", nil, nil),
+				NewProp("stats", 1, nil),
+			}),
+		)))
+
+		records := logRecords(t, &buf)
+		require.Len(t, records, 1)
+
+		record := records[0]
+		assert.Equal(t, "DEBUG", record["level"])
+		assert.Equal(t, "inertia: resolved props", record["msg"])
+		assert.Equal(t, "Users", record["component"])
+		assert.Equal(t, true, record["inertia"])
+		assert.Equal(t, true, record["partial"])
+		assert.Equal(t, "1", record["version"])
+		assert.Equal(t, "createUser", record["error_bag"])
+		assert.Equal(t, []any{"users", "filters"}, record["partial_data"])
+		assert.Equal(t, []any{"errors", "filters", "users"}, record["props"])
+	})
+
+	t.Run("discard by default", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, nil)
+		assert.Same(t, DiscardLogger, renderer.Logger())
+	})
+}
+
+func TestMiddleware_Logger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	renderer := New(testTpl, &Config{Version: "2"})
+	h := Middleware(renderer, func(c *MiddlewareConfig) {
+		c.Logger = newTestLogger(&buf)
+	})(http.NotFoundHandler())
+
+	req, w := inertiatest.NewRequest(http.MethodGet, "/users?page=2", &inertiatest.RequestConfig{
+		Inertia: true,
+		Version: "1",
+	})
+
+	h.ServeHTTP(w, req)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "inertia: asset version mismatch", record["msg"])
+	assert.Equal(t, "1", record["client_version"])
+	assert.Equal(t, "2", record["server_version"])
+	assert.Equal(t, "/users?page=2", record["url"])
+}
+
+func TestLoggerFromRequest(t *testing.T) {
+	t.Parallel()
+
+	req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+	assert.Same(t, DiscardLogger, LoggerFromRequest(req), "without renderer")
+}
@@ -1,8 +1,10 @@
 package inertia
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"log/slog"
 	"net/http"
 	"slices"
 
@@ -27,7 +29,14 @@ type MiddlewareConfig struct {
 	EmptyResponseHandler http.HandlerFunc
 
 	// VersionMismatchHandler is a function that is called when the version mismatch occurs.
+	//
+	// It defaults to a handler redirecting to the URL resolved by RequestURL.
 	VersionMismatchHandler http.HandlerFunc
+
+	// Logger is used to log version mismatches and empty responses.
+	//
+	// It defaults to the logger of the Renderer.
+	Logger *slog.Logger
 }
 
 func (m *MiddlewareConfig) defaults() {
@@ -39,7 +48,7 @@ func (m *MiddlewareConfig) defaults() {
 
 	if m.VersionMismatchHandler == nil {
 		m.VersionMismatchHandler = func(w http.ResponseWriter, r *http.Request) {
-			Location(w, r, r.RequestURI)
+			Location(w, r, RequestURL(r))
 		}
 	}
 }
@@ -53,6 +62,7 @@ func Middleware(renderer *Renderer, opts ...func(*MiddlewareConfig)) httpmiddlew
 	}
 
 	config.defaults()
+	config.Logger = cmp.Or(config.Logger, renderer.Logger())
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -68,7 +78,18 @@ func Middleware(renderer *Renderer, opts ...func(*MiddlewareConfig)) httpmiddlew
 
 			externalVersion := r.Header.Get(inertiaheader.HeaderXInertiaVersion)
 			if externalVersion != renderer.Version() {
-				Location(w, r, r.RequestURI)
+				config.Logger.LogAttrs(r.Context(), slog.LevelInfo, "inertia: asset version mismatch",
+					slog.String("client_version", externalVersion),
+					slog.String("server_version", renderer.Version()),
+					slog.String("url", RequestURL(r)),
+				)
+				renderer.hooks.versionMismatch(r.Context(), VersionMismatchInfo{
+					Request:       r,
+					ClientVersion: externalVersion,
+					ServerVersion: renderer.Version(),
+				})
+
+				config.VersionMismatchHandler(w, r)
 				return
 			}
 
@@ -81,6 +102,11 @@ func Middleware(renderer *Renderer, opts ...func(*MiddlewareConfig)) httpmiddlew
 			}
 
 			if rww.Empty() {
+				config.Logger.LogAttrs(r.Context(), slog.LevelWarn, "inertia: empty response",
+					slog.String("method", r.Method),
+					slog.String("url", RequestURL(r)),
+				)
+
 				config.EmptyResponseHandler(w, r)
 				return
 			}
@@ -93,12 +119,16 @@ func Middleware(renderer *Renderer, opts ...func(*MiddlewareConfig)) httpmiddlew
 // RenderContext represents an Inertia.js page context.
 type RenderContext struct {
 	T                 any // T is an optional custom data that can be passed to the template.
+	RootTemplate      string
+	Head              Head // Head is rendered into the template when SSR is disabled.
 	Props             []Prop
 	ErrorBag          string
 	ValidationErrorer []ValidationErrorer
+	Extensions        map[string]any // Extensions are additional top-level page fields.
 	EncryptHistory    bool
 	ClearHistory      bool
 	Concurrency       int
+	Status            int // Status is the response status code, it defaults to 200 OK.
 }
 
 // NewRenderContext creates a new RenderContext with the provided options.
@@ -135,6 +165,34 @@ func WithEncryptHistory() Option {
 	return func(opt *RenderContext) { opt.EncryptHistory = true }
 }
 
+// WithStatus sets the response status code.
+//
+// ETags are computed only for 200 OK responses.
+func WithStatus(code int) Option {
+	return func(opt *RenderContext) { opt.Status = code }
+}
+
+// WithRootTemplate sets the name of the root template to render the page with.
+//
+// It takes precedence over the Config.RootTemplates rules.
+func WithRootTemplate(name string) Option {
+	return func(opt *RenderContext) { opt.RootTemplate = name }
+}
+
+// WithExtension sets an additional top-level page field.
+//
+// Calling this function multiple times with the same key will override
+// the previous value. The key must not match any of the Page fields.
+func WithExtension(key string, value any) Option {
+	return func(renderCtx *RenderContext) {
+		if renderCtx.Extensions == nil {
+			renderCtx.Extensions = make(map[string]any, 1)
+		}
+
+		renderCtx.Extensions[key] = value
+	}
+}
+
 // WithProps sets the props for the page.
 //
 // Calling this function multiple times will append the props.
@@ -0,0 +1,53 @@
+package inertia
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+
+	"go.inout.gg/foundations/http/httpmiddleware"
+)
+
+// nonceSize is the number of random bytes used to generate a nonce.
+const nonceSize = 16
+
+type nonceCtxKey struct{}
+
+//nolint:gochecknoglobals
+var kNonceCtxKey = nonceCtxKey{}
+
+// NonceMiddleware generates a random Content-Security-Policy nonce for
+// every request and stores it in the request context.
+//
+// The nonce is available to the root template as TemplateData.Nonce and
+// can be retrieved with NonceFromContext, e.g., to build the
+// Content-Security-Policy header.
+func NonceMiddleware() httpmiddleware.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ContextWithNonce(r.Context(), newNonce())))
+		})
+	}
+}
+
+// ContextWithNonce returns a copy of ctx carrying the Content-Security-Policy
+// nonce.
+func ContextWithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, kNonceCtxKey, nonce)
+}
+
+// NonceFromContext returns the Content-Security-Policy nonce stored in ctx,
+// or an empty string if there is none.
+func NonceFromContext(ctx context.Context) string {
+	nonce, _ := ctx.Value(kNonceCtxKey).(string)
+	return nonce
+}
+
+// newNonce generates a new random base64 encoded nonce.
+func newNonce() string {
+	b := make([]byte, nonceSize)
+	_, _ = rand.Read(b) // never returns an error
+
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,86 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestNonceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var nonces []string
+
+	h := NonceMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, NonceFromContext(r.Context()))
+	}))
+
+	for range 2 {
+		r, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		h.ServeHTTP(w, r)
+	}
+
+	require.Len(t, nonces, 2)
+	assert.NotEmpty(t, nonces[0])
+	assert.NotEqual(t, nonces[0], nonces[1], "nonce should be unique per request")
+}
+
+func TestRenderer_Nonce(t *testing.T) {
+	t.Parallel()
+
+	t.Run("template data", func(t *testing.T) {
+		t.Parallel()
+
+		tpl := template.Must(template.New("root").Parse(`<script nonce="{{ .Nonce }}"></script>`))
+		renderer := New(tpl, nil)
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		var buf bytes.Buffer
+
+		ctx := ContextWithNonce(t.Context(), "abc123")
+		require.NoError(t, renderer.RenderHTML(ctx, &buf, req, "Home", NewRenderContext()))
+
+		assert.Equal(t, `<script nonce="abc123"></script>`, buf.String())
+	})
+
+	t.Run("ssr payload", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			payload map[string]any
+			nonce   string
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce = r.Header.Get(inertiaheader.HeaderXInertiaNonce)
+
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(b, &payload))
+
+			assert.NoError(t, json.NewEncoder(w).Encode(&SsrTemplateData{Head: "", Body: ""}))
+		}))
+		defer server.Close()
+
+		client := NewHTTPSsrClient(server.URL, nil)
+
+		page := &Page{Component: "Home", Extensions: map[string]any{"nonce": "user"}}
+
+		_, err := client.Render(ContextWithNonce(t.Context(), "abc123"), page)
+		require.NoError(t, err)
+
+		assert.Equal(t, "abc123", nonce)
+		assert.Equal(t, "user", payload["nonce"], "the nonce must not overwrite page extensions")
+		assert.Equal(t, "Home", payload["component"])
+	})
+}
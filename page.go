@@ -0,0 +1,110 @@
+package inertia
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pageField is a JSON serializable field of Page.
+type pageField struct {
+	name      string
+	index     int
+	omitempty bool
+}
+
+// pageFields is a list of the JSON serializable fields of Page.
+//
+//nolint:gochecknoglobals
+var pageFields = func() []pageField {
+	typ := reflect.TypeFor[Page]()
+	fields := make([]pageField, 0, typ.NumField())
+
+	for i := range typ.NumField() {
+		name, opts, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields = append(fields, pageField{
+			name:      name,
+			index:     i,
+			omitempty: strings.Contains(opts, "omitempty"),
+		})
+	}
+
+	return fields
+}()
+
+// pageJSON is Page without its MarshalJSON method.
+type pageJSON Page
+
+// MarshalJSON implements json.Marshaler.
+//
+// The extensions are serialized as top-level fields of the page.
+// It returns an error if an extension conflicts with a page field.
+func (p Page) MarshalJSON() ([]byte, error) { //nolint:gocritic // Page values must be serialized as well
+	v, err := p.encodable()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v) //nolint:wrapcheck
+}
+
+// encodable returns a value to be passed to a JSONEncoder
+// to serialize the page.
+//
+// If the page has extensions, the page is flattened into a map with
+// the extensions as top-level fields, otherwise the page itself is returned
+// as a pageJSON, so the JSONEncoder does not call Page.MarshalJSON.
+func (p *Page) encodable() (any, error) {
+	if len(p.Extensions) == 0 {
+		return (*pageJSON)(p), nil
+	}
+
+	m := make(map[string]any, len(pageFields)+len(p.Extensions))
+	val := reflect.ValueOf(p).Elem()
+
+	for _, f := range pageFields {
+		fv := val.Field(f.index)
+		if f.omitempty && isEmptyValue(fv) {
+			continue
+		}
+
+		m[f.name] = fv.Interface()
+	}
+
+	for key, value := range p.Extensions {
+		if p.isField(key) {
+			return nil, fmt.Errorf("inertia: page extension %q conflicts with a page field", key)
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// isField reports whether key is a JSON field name of Page.
+func (p *Page) isField(key string) bool {
+	for _, f := range pageFields {
+		if f.name == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmptyValue reports whether v is empty according to the omitempty
+// option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,126 @@
+package inertia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestRenderer_Extensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serialized at top level", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{Version: "1.0.0"})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(
+			WithExtension("meta", map[string]string{"build": "abc123"}),
+			WithExtension("sharedPropsKeys", []string{"auth"}),
+		)))
+
+		var page map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.Equal(t, map[string]any{"build": "abc123"}, page["meta"])
+		assert.Equal(t, []any{"auth"}, page["sharedPropsKeys"])
+		assert.Equal(t, "Home", page["component"])
+		assert.Equal(t, "1.0.0", page["version"])
+		assert.Equal(t, false, page["encryptHistory"])
+		assert.NotContains(t, page, "deferredProps", "empty omitempty fields must be omitted")
+		assert.NotContains(t, page, "Extensions")
+	})
+
+	t.Run("without extensions", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, nil)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext()))
+
+		var page map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.NotContains(t, page, "Extensions")
+	})
+
+	t.Run("conflicting extension", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, nil)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		err := renderer.Render(w, req, "Home", NewRenderContext(WithExtension("component", "Other")))
+		require.Error(t, err)
+	})
+
+	t.Run("set by page transformer", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{
+			PageTransformers: []PageTransformer{
+				func(_ context.Context, _ *http.Request, page *Page) error {
+					page.Extensions = map[string]any{"rememberedState": map[string]any{}}
+					return nil
+				},
+			},
+		})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext()))
+
+		assert.Contains(t, w.Body.String(), "&#34;rememberedState&#34;:{}")
+	})
+}
+
+func TestPage_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	page := Page{
+		Props:      map[string]any{"title": "Home"},
+		Component:  "Home",
+		URL:        "/",
+		Extensions: map[string]any{"meta": "abc123"},
+	}
+
+	for name, v := range map[string]any{"value": page, "pointer": &page} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(b, &m))
+
+			assert.Equal(t, "abc123", m["meta"])
+			assert.Equal(t, "Home", m["component"])
+			assert.Equal(t, map[string]any{"title": "Home"}, m["props"])
+			assert.NotContains(t, m, "Extensions")
+			assert.NotContains(t, m, "mergeProps")
+		})
+	}
+
+	t.Run("without extensions", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := json.Marshal(&Page{Component: "Home"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"props":null,"component":"Home","url":"","version":"","encryptHistory":false,"clearHistory":false}`, string(b))
+	})
+
+	t.Run("conflicting extension", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := json.Marshal(&Page{Extensions: map[string]any{"url": "/other"}})
+		require.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package inertia
 import (
 	"cmp"
 	"context"
+	"fmt"
 )
 
 var (
@@ -28,6 +29,7 @@ type Prop struct {
 	lazy       bool // optional, deferred
 	ignorable  bool // false if always prop
 	concurrent bool // deferred
+	sensitive  bool
 }
 
 // DeferredOptions represents a.
@@ -48,6 +50,14 @@ type DeferredOptions struct {
 	// Properties marked as concurrent are grouped in a separate batch
 	// and resolved concurrently.
 	Concurrent bool
+
+	// Sensitive marks the prop value as sensitive.
+	//
+	// If a sensitive prop is declared for the page, the history encryption
+	// is enforced, even if the prop is not included in the response, e.g.,
+	// a deferred prop or a partial reload. The value is redacted in
+	// debug logs.
+	Sensitive bool
 }
 
 type (
@@ -91,6 +101,7 @@ func NewDeferred(key string, fn Lazy, opts *DeferredOptions) Prop {
 		prop.group = cmp.Or(opts.Group, DefaultDeferredGroup)
 		prop.mergeable = opts.Merge
 		prop.concurrent = opts.Concurrent
+		prop.sensitive = opts.Sensitive
 	}
 
 	return prop
@@ -123,6 +134,14 @@ func NewOptional(key string, fn Lazy) Prop {
 type PropOptions struct {
 	// Merge indicates whether the prop can be merged with other props.
 	Merge bool
+
+	// Sensitive marks the prop value as sensitive.
+	//
+	// If a sensitive prop is declared for the page, the history encryption
+	// is enforced, even if the prop is not included in the response, e.g.,
+	// a deferred prop or a partial reload. The value is redacted in
+	// debug logs.
+	Sensitive bool
 }
 
 // NewProp creates a new regular prop.
@@ -137,6 +156,7 @@ func NewProp(key string, val any, opts *PropOptions) Prop {
 
 	if opts != nil {
 		prop.mergeable = opts.Merge
+		prop.sensitive = opts.Sensitive
 	}
 
 	return prop
@@ -159,6 +179,18 @@ func (p Prop) value(ctx context.Context) (any, error) {
 	return p.val, nil
 }
 
+// PropError is returned when a prop value fails to resolve.
+type PropError struct {
+	Err error
+	Key string
+}
+
+func (e *PropError) Error() string {
+	return fmt.Sprintf("inertia: failed to resolve prop %s: %v", e.Key, e.Err)
+}
+
+func (e *PropError) Unwrap() error { return e.Err }
+
 // Proper is an interface that represents a collection of props.
 // It is used to attach props to the rendering context.
 type Proper interface {
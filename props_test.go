@@ -103,6 +103,19 @@ func TestProps(t *testing.T) {
 			assert.False(t, prop.mergeable)
 			assert.True(t, prop.concurrent)
 		})
+
+		t.Run("Sensitive", func(t *testing.T) {
+			t.Parallel()
+
+			prop := NewDeferred(
+				"key",
+				LazyFunc(func(context.Context) (any, error) { return "val", nil }),
+				&DeferredOptions{Sensitive: true},
+			)
+
+			assert.True(t, prop.deferred)
+			assert.True(t, prop.sensitive)
+		})
 	})
 
 	t.Run("NewAlways", func(t *testing.T) {
@@ -172,6 +185,16 @@ func TestProps(t *testing.T) {
 			assert.False(t, prop.deferred)
 			assert.True(t, prop.mergeable)
 			assert.False(t, prop.concurrent)
+			assert.False(t, prop.sensitive)
+		})
+
+		t.Run("Sensitive", func(t *testing.T) {
+			t.Parallel()
+
+			prop := NewProp("key", "val", &PropOptions{Sensitive: true})
+
+			assert.True(t, prop.sensitive)
+			assert.False(t, prop.mergeable)
 		})
 	})
 }
@@ -200,3 +223,35 @@ func TestPropsCollections(t *testing.T) {
 		assert.Equal(t, "val2", val)
 	})
 }
+
+func TestParseStruct_Sensitive(t *testing.T) {
+	t.Parallel()
+
+	type message struct {
+		Name     string   `inertia:"name"`
+		APIKey   string   `inertia:"api_key,,sensitive"`
+		Token    string   `inertia:"token,always,sensitive"`
+		Optional LazyFunc `inertia:"optional,optional,sensitive"`
+		Deferred LazyFunc `inertia:"deferred,deferred,mergeable,sensitive"`
+	}
+
+	fn := LazyFunc(func(context.Context) (any, error) { return "val", nil })
+
+	props, err := ParseStruct(&message{Name: "name", APIKey: "key", Token: "token", Optional: fn, Deferred: fn})
+	require.NoError(t, err)
+	require.Len(t, props, 5)
+
+	sensitive := make(map[string]bool, len(props))
+	for _, p := range props {
+		sensitive[p.key] = p.sensitive
+	}
+
+	assert.Equal(t, map[string]bool{
+		"name":     false,
+		"api_key":  true,
+		"token":    true,
+		"optional": true,
+		"deferred": true,
+	}, sensitive)
+	assert.True(t, props[4].mergeable)
+}
@@ -0,0 +1,138 @@
+package inertia
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// DefaultFallbackRedirectURL is the default URL used in place of
+// a disallowed redirect target.
+const DefaultFallbackRedirectURL = "/"
+
+// redirectPolicy validates redirect targets.
+type redirectPolicy struct {
+	fallback     string
+	allowedHosts []string
+}
+
+// defaultRedirectPolicy is the policy used when there is no Renderer
+// attached to the request, it allows same-origin targets only.
+//
+//nolint:gochecknoglobals
+var defaultRedirectPolicy = &redirectPolicy{fallback: DefaultFallbackRedirectURL, allowedHosts: nil}
+
+// SafeRedirectURL returns target if it is a safe redirect target for req,
+// otherwise it returns the fallback redirect URL.
+//
+// An empty target is replaced with the fallback redirect URL as well.
+//
+// It uses the configuration of the Renderer attached to req by the
+// Middleware. Without a Renderer, only same-origin targets are allowed and
+// the fallback is DefaultFallbackRedirectURL.
+//
+// See Config.AllowedRedirectHosts for the rules.
+func SafeRedirectURL(req *http.Request, target string) string {
+	renderer, ok := req.Context().Value(kCtxKey).(*Renderer)
+	if !ok {
+		return defaultRedirectPolicy.safeURL(req, target, DefaultURLResolver(req), DiscardLogger)
+	}
+
+	return renderer.SafeRedirectURL(req, target)
+}
+
+// SafeRedirectURL returns target if it is a safe redirect target for req,
+// otherwise it returns Config.FallbackRedirectURL.
+func (r *Renderer) SafeRedirectURL(req *http.Request, target string) string {
+	return r.redirectPolicy.safeURL(req, target, r.URL(req), r.logger)
+}
+
+// safeURL returns target if it is allowed, otherwise the fallback URL.
+//
+// pageURL is the URL of the request as seen by the client, its host is
+// considered same-origin, e.g., when the request is forwarded by a proxy.
+func (p *redirectPolicy) safeURL(req *http.Request, target, pageURL string, logger *slog.Logger) string {
+	if p.allowed(req, target, pageURL) {
+		return target
+	}
+
+	if target == "" {
+		return p.fallback
+	}
+
+	d("Rejected redirect target %q, falling back to %q", target, p.fallback)
+
+	logger.LogAttrs(req.Context(), slog.LevelWarn, "inertia: rejected redirect target",
+		slog.String("target", target),
+		slog.String("fallback", p.fallback),
+	)
+
+	return p.fallback
+}
+
+// allowed reports whether target is a relative URL, or an http(s) URL with
+// the request host or one of the allowed hosts.
+func (p *redirectPolicy) allowed(req *http.Request, target, pageURL string) bool {
+	// Browsers strip leading and trailing whitespace and control
+	// characters, and treat backslashes as slashes, e.g., " //evil.com"
+	// and "/\evil.com" are protocol-relative URLs.
+	if target == "" || strings.ContainsFunc(target, isUnsafeURLRune) {
+		return false
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" && u.Host == "" {
+		// Browsers collapse extra slashes, e.g., "///evil.com" is
+		// a protocol-relative URL.
+		return u.User == nil && !strings.HasPrefix(u.Path, "//")
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return false
+	}
+
+	if strings.EqualFold(u.Host, req.Host) {
+		return true
+	}
+
+	if pu, err := url.Parse(pageURL); err == nil && pu.Host != "" && strings.EqualFold(u.Host, pu.Host) {
+		return true
+	}
+
+	for _, host := range p.allowedHosts {
+		if matchHost(host, u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnsafeURLRune reports whether r is a whitespace, a control character or
+// a backslash, all of which browsers normalize before resolving a URL.
+func isUnsafeURLRune(r rune) bool {
+	return r <= ' ' || r == 0x7f || r == '\\' || unicode.IsSpace(r)
+}
+
+// matchHost reports whether the host of u matches the pattern.
+//
+// A pattern without a port matches any port. A pattern starting with "*."
+// matches any subdomain.
+func matchHost(pattern string, u *url.URL) bool {
+	host := u.Host
+	if !strings.Contains(pattern, ":") {
+		host = u.Hostname()
+	}
+
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+		return len(host) > len(suffix) && strings.HasSuffix(strings.ToLower(host), strings.ToLower(suffix))
+	}
+
+	return strings.EqualFold(pattern, host)
+}
@@ -0,0 +1,119 @@
+package inertia
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestSafeRedirectURL(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{
+		AllowedRedirectHosts: []string{"accounts.example.org", "*.example.net", "localhost:3000"},
+		FallbackRedirectURL:  "/home",
+	})
+
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{name: "relative path", target: "/users?page=2", expected: "/users?page=2"},
+		{name: "relative path without slash", target: "users", expected: "users"},
+		{name: "same origin", target: "http://example.com/users", expected: "http://example.com/users"},
+		{name: "same origin case insensitive", target: "https://EXAMPLE.com/users", expected: "https://EXAMPLE.com/users"},
+		{name: "allowed host", target: "https://accounts.example.org/login", expected: "https://accounts.example.org/login"},
+		{name: "allowed host any port", target: "https://accounts.example.org:8443/", expected: "https://accounts.example.org:8443/"},
+		{name: "allowed subdomain", target: "https://api.example.net/", expected: "https://api.example.net/"},
+		{name: "allowed host with port", target: "http://localhost:3000/", expected: "http://localhost:3000/"},
+		{name: "wildcard apex", target: "https://example.net/", expected: "/home"},
+		{name: "host with other port", target: "http://localhost:4000/", expected: "/home"},
+		{name: "other host", target: "https://evil.com/", expected: "/home"},
+		{name: "suffix host", target: "https://evilexample.net/", expected: "/home"},
+		{name: "protocol relative", target: "//evil.com/", expected: "/home"},
+		{name: "backslash", target: "/\\evil.com", expected: "/home"},
+		{name: "control character", target: "/\t/evil.com", expected: "/home"},
+		{name: "leading space", target: " //evil.com", expected: "/home"},
+		{name: "trailing space", target: "/users ", expected: "/home"},
+		{name: "triple slash", target: "///evil.com", expected: "/home"},
+		{name: "triple slash with backslash", target: "/\\/evil.com", expected: "/home"},
+		{name: "path with double slash", target: "/users//edit", expected: "/users//edit"},
+		{name: "userinfo", target: "https://example.com@evil.com/", expected: "/home"},
+		{name: "javascript scheme", target: "javascript:alert(1)", expected: "/home"},
+		{name: "scheme without host", target: "https:evil.com", expected: "/home"},
+		{name: "empty", target: "", expected: "/home"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), kCtxKey, renderer))
+
+			assert.Equal(t, tt.expected, SafeRedirectURL(req, tt.target))
+		})
+	}
+
+	t.Run("without renderer", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.Equal(t, "/users", SafeRedirectURL(req, "/users"))
+		assert.Equal(t, "http://example.com/", SafeRedirectURL(req, "http://example.com/"))
+		assert.Equal(t, DefaultFallbackRedirectURL, SafeRedirectURL(req, "https://accounts.example.org/"))
+	})
+
+	t.Run("logs rejected target", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		renderer := New(testTpl, &Config{Logger: newTestLogger(&buf)})
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.Equal(t, DefaultFallbackRedirectURL, renderer.SafeRedirectURL(req, "https://evil.com/"))
+		assert.Contains(t, buf.String(), `"msg":"inertia: rejected redirect target"`)
+		assert.Contains(t, buf.String(), `"target":"https://evil.com/"`)
+	})
+}
+
+func TestRedirect_Disallowed(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, nil)
+
+	t.Run("redirect", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodPost, "/users", &inertiatest.RequestConfig{Inertia: true})
+		req = req.WithContext(context.WithValue(req.Context(), kCtxKey, renderer))
+
+		Redirect(w, req, "https://evil.com/")
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, DefaultFallbackRedirectURL, w.Header().Get("Location"))
+	})
+
+	t.Run("location", func(t *testing.T) {
+		t.Parallel()
+
+		for _, target := range []string{"//evil.com/", " //evil.com", "///evil.com"} {
+			req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{Inertia: true})
+			req = req.WithContext(context.WithValue(req.Context(), kCtxKey, renderer))
+
+			Location(w, req, target)
+
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Equal(t, DefaultFallbackRedirectURL, w.Header().Get(inertiaheader.HeaderXInertiaLocation), target)
+		}
+	})
+}
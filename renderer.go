@@ -8,14 +8,18 @@ import (
 	"bytes"
 	"cmp"
 	"context"
-	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
+	"log/slog"
+	"maps"
 	"net/http"
+	"path"
 	"runtime"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/alitto/pond/v2"
 	"go.inout.gg/foundations/debug"
@@ -30,6 +34,9 @@ const (
 	contentTypeJSON = "application/json"
 )
 
+// redacted replaces sensitive values in debug output.
+const redacted = "[REDACTED]"
+
 const (
 	// DefaultRootViewID is the default root HTML element ID to which
 	// the Inertia.js app is mounted.
@@ -50,6 +57,16 @@ type Page struct {
 	MergeProps     []string            `json:"mergeProps,omitempty"`
 	EncryptHistory bool                `json:"encryptHistory"`
 	ClearHistory   bool                `json:"clearHistory"`
+
+	// Extensions are additional top-level page fields, e.g., fields used by
+	// client-side plugins or protocol fields not yet supported by Page.
+	//
+	// Extensions are serialized by Page.MarshalJSON and must not override
+	// the Page fields.
+	Extensions map[string]any `json:"-"`
+
+	// sensitiveProps is a list of props keys with sensitive values.
+	sensitiveProps []string
 }
 
 // Config represents the configuration for the Renderer.
@@ -64,6 +81,122 @@ type Config struct {
 	// It defaults to "app".
 	RootViewID string
 
+	// UseScriptElement embeds the initial page into a
+	// <script type="application/json" data-page="<RootViewID>"> element
+	// instead of the data-page attribute of the root element.
+	//
+	// It avoids the HTML attribute escaping overhead for large pages.
+	// The client must be configured to read the initial page from the script
+	// element, e.g., using the useScriptElementForInitialPage option.
+	UseScriptElement bool
+
+	// RootTemplates selects a named root template by the component name.
+	//
+	// Rules are matched in order and the first match wins. If no rule
+	// matches, the template passed to New is executed.
+	//
+	// A root template set for a given render using WithRootTemplate
+	// takes precedence over the rules.
+	RootTemplates []RootTemplate
+
+	// ETag enables conditional GET support.
+	//
+	// If enabled, a strong ETag is computed over the encoded response and
+	// requests with a matching If-None-Match header are answered with
+	// 304 Not Modified, for both Inertia.js and HTML responses.
+	// The response is buffered to compute the ETag.
+	//
+	// Note that HTML responses embedding per-request data, e.g., a nonce,
+	// never match.
+	ETag bool
+
+	// MaxPropSize is the maximum encoded size of a single prop in bytes.
+	//
+	// When running without -tags=production, a render exceeding it fails
+	// with a BudgetExceededError. Otherwise the overrun is logged and
+	// reported through Hooks.BudgetExceeded.
+	//
+	// If it is zero, the prop size is not checked. Props are encoded once
+	// more to be measured, but only if the page is at least MaxPropSize
+	// bytes.
+	MaxPropSize int
+
+	// MaxPageSize is the maximum encoded size of a page in bytes.
+	//
+	// It is enforced the same way as MaxPropSize. The page encoded for
+	// the response is measured, so the page is encoded once more only by
+	// RenderPage and with an SsrClient.
+	//
+	// If it is zero, the page size is not checked.
+	MaxPageSize int
+
+	// ServerTiming enables the Server-Timing header on responses sent
+	// by Render.
+	//
+	// The header reports the total props resolution, each prop taking
+	// at least ServerTimingThreshold to resolve, the SsrClient call and
+	// the root template execution.
+	// HTML responses are buffered to set the header.
+	ServerTiming bool
+
+	// ServerTimingThreshold is the duration from which a prop is reported
+	// individually in the Server-Timing header.
+	//
+	// It defaults to DefaultServerTimingThreshold.
+	ServerTimingThreshold time.Duration
+
+	// PageTransformers are called in order on every page after it is created
+	// and before it is serialized or sent to the SsrClient.
+	//
+	// A transformer can modify the page in place, e.g., to inject build
+	// metadata or strip props. If a transformer returns an error,
+	// the rendering is aborted.
+	PageTransformers []PageTransformer
+
+	// URLResolver resolves the page URL as seen by the client.
+	//
+	// Set it to ForwardedURLResolver, if the application is mounted under
+	// a path prefix behind a reverse proxy.
+	//
+	// It defaults to DefaultURLResolver.
+	URLResolver URLResolver
+
+	// FallbackRedirectURL is the URL used in place of a redirect target
+	// that is not allowed, see AllowedRedirectHosts.
+	//
+	// It defaults to DefaultFallbackRedirectURL.
+	FallbackRedirectURL string
+
+	// AllowedRedirectHosts are the hosts, besides the request host,
+	// that Location, Redirect and inertiaframe.RedirectBack may redirect to.
+	//
+	// A host without a port matches any port, e.g., "example.com" matches
+	// "example.com:8080". A host starting with "*." matches any subdomain,
+	// e.g., "*.example.com" matches "api.example.com", but not "example.com".
+	//
+	// Relative URLs are always allowed. Absolute URLs must use the http or
+	// https scheme. If it is empty, only same-origin redirects are allowed.
+	AllowedRedirectHosts []string
+
+	// Hooks are called during the render lifecycle, e.g., to record
+	// traces and metrics.
+	//
+	// It is optional.
+	Hooks *Hooks
+
+	// Logger is used to log structured records about rendered pages,
+	// e.g., the component and the requested partial props.
+	//
+	// It defaults to DiscardLogger.
+	Logger *slog.Logger
+
+	// JSONEncoder is used to serialize pages, both for Inertia.js responses
+	// and HTML responses, including the payload sent to the HTTP SsrClient
+	// created by NewHTTPSsrClient.
+	//
+	// It defaults to DefaultJSONEncoder.
+	JSONEncoder JSONEncoder
+
 	// Concurrency controls the number of concurrent props resolution.
 	//
 	// Only those props marked as concurrent are resolved concurrently.
@@ -72,10 +205,33 @@ type Config struct {
 	Concurrency int
 }
 
+// PageTransformer transforms the page before it is serialized.
+type PageTransformer func(context.Context, *http.Request, *Page) error
+
+// RootTemplate maps components matching Pattern to the root template Name.
+type RootTemplate struct {
+	// Pattern is a component name pattern in the path.Match syntax,
+	// e.g., "Admin/*" matches "Admin/Users", but not "Admin/Users/Edit".
+	Pattern string
+
+	// Name is the name of the root template as defined in the template
+	// passed to New, e.g., a file name when using FromFS.
+	Name string
+}
+
 // defaults sets the default values for the configuration.
 func (c *Config) defaults() {
 	c.RootViewID = cmp.Or(c.RootViewID, DefaultRootViewID)
 	c.Concurrency = cmp.Or(c.Concurrency, DefaultConcurrency)
+	c.JSONEncoder = cmp.Or(c.JSONEncoder, DefaultJSONEncoder)
+
+	c.Logger = cmp.Or(c.Logger, DiscardLogger)
+	c.ServerTimingThreshold = cmp.Or(c.ServerTimingThreshold, DefaultServerTimingThreshold)
+	c.FallbackRedirectURL = cmp.Or(c.FallbackRedirectURL, DefaultFallbackRedirectURL)
+
+	if c.URLResolver == nil {
+		c.URLResolver = DefaultURLResolver
+	}
 }
 
 // Renderer is a renderer that sends Inertia.js responses.
@@ -84,12 +240,26 @@ func (c *Config) defaults() {
 //
 // To create a new Renderer, use the New or FromFS functions.
 type Renderer struct {
-	ssrClient     SsrClient
-	t             *template.Template
-	rootViewID    string
-	version       string
-	rootViewAttrs []pair[[]byte, []byte]
-	concurrency   int
+	ssrClient        SsrClient
+	encoder          JSONEncoder
+	urlResolver      URLResolver
+	redirectPolicy   *redirectPolicy
+	t                *template.Template
+	rootViewID       string
+	version          string
+	rootViewAttrs    []pair[[]byte, []byte]
+	rootTemplates    []RootTemplate
+	transformers     []PageTransformer
+	hooks            *Hooks
+	logger           *slog.Logger
+	concurrency      int
+	maxPropSize      int
+	maxPageSize      int
+	timingThreshold  time.Duration
+	useScriptElement bool
+	etag             bool
+	serverTiming     bool
+	strictBudget     bool
 }
 
 // New creates a new Renderer instance.
@@ -109,17 +279,40 @@ func New(t *template.Template, config *Config) *Renderer {
 	}
 
 	r := &Renderer{
-		t:             t,
-		ssrClient:     config.SsrClient,
-		version:       config.Version,
-		rootViewID:    config.RootViewID,
-		rootViewAttrs: attrs,
-		concurrency:   config.Concurrency,
+		t:           t,
+		ssrClient:   withSsrJSONEncoder(config.SsrClient, config.JSONEncoder),
+		encoder:     config.JSONEncoder,
+		urlResolver: config.URLResolver,
+		redirectPolicy: &redirectPolicy{
+			fallback:     config.FallbackRedirectURL,
+			allowedHosts: slices.Clone(config.AllowedRedirectHosts),
+		},
+		version:          config.Version,
+		rootViewID:       config.RootViewID,
+		rootViewAttrs:    attrs,
+		rootTemplates:    slices.Clone(config.RootTemplates),
+		transformers:     slices.Clone(config.PageTransformers),
+		hooks:            config.Hooks,
+		logger:           config.Logger,
+		concurrency:      config.Concurrency,
+		useScriptElement: config.UseScriptElement,
+		etag:             config.ETag,
+		serverTiming:     config.ServerTiming,
+		timingThreshold:  config.ServerTimingThreshold,
+		maxPropSize:      config.MaxPropSize,
+		maxPageSize:      config.MaxPageSize,
+		strictBudget:     development,
 	}
 
 	debug.Assert(r.t != nil, "expected t to be defined")
 	debug.Assert(r.rootViewID != "", "expected RootViewID to be defined")
 
+	for _, rt := range r.rootTemplates {
+		_, err := path.Match(rt.Pattern, "")
+		debug.Assert(err == nil, "expected root template pattern to be valid")
+		debug.Assert(r.t.Lookup(rt.Name) != nil, "expected root template to be defined")
+	}
+
 	return r
 }
 
@@ -147,43 +340,261 @@ func MustFromFS(fsys fs.FS, path string, config *Config) *Renderer {
 // Version returns a version of the inertia build.
 func (r *Renderer) Version() string { return r.version }
 
+// URL returns the URL of the request as seen by the client.
+func (r *Renderer) URL(req *http.Request) string { return r.urlResolver(req) }
+
 // Render sends a page component using Inertia.js protocol.
 // If the request is an Inertia.js request, the response will be JSON,
 // otherwise, it will be an HTML response.
-func (r *Renderer) Render(w http.ResponseWriter, req *http.Request, name string, renderCtx RenderContext) error {
-	renderCtx.Concurrency = cmp.Or(renderCtx.Concurrency, r.concurrency)
-	if renderCtx.Concurrency < 0 {
-		renderCtx.Concurrency = 0
+func (r *Renderer) Render(w http.ResponseWriter, req *http.Request, name string, renderCtx RenderContext) (err error) {
+	ctx, done := r.startRender(req.Context(), req, name)
+
+	var page *Page
+	defer func() { done(page, err) }()
+
+	var timing *serverTiming
+	if r.serverTiming {
+		ctx, timing = withServerTiming(ctx, r.timingThreshold)
 	}
 
-	page, err := r.newPage(req, name, renderCtx)
+	page, err = r.renderPage(ctx, req, name, renderCtx)
 	if err != nil {
 		return err
 	}
 
+	status := cmp.Or(renderCtx.Status, http.StatusOK)
+	etag := r.etag && status == http.StatusOK
+
 	if isInertiaRequest(req) {
 		d("Received inertia request, sending JSON response: %s",
 			req.Header.Get(inertiaheader.HeaderReferer))
 
 		w.Header().Set(inertiaheader.HeaderXInertia, "true")
 		w.Header().Set(inertiaheader.HeaderContentType, contentTypeJSON)
-		w.WriteHeader(http.StatusOK)
+		timing.write(w)
 
-		err := json.NewEncoder(w).Encode(page)
+		b, release, err := r.encodePage(ctx, req, page)
 		if err != nil {
-			return fmt.Errorf("inertia: failed to encode JSON response: %w", err)
+			return err
+		}
+		defer release()
+
+		if etag {
+			return writeWithETag(w, req, b)
+		}
+
+		w.WriteHeader(status)
+
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("inertia: failed to write response: %w", err)
 		}
 
 		return nil
 	}
 
 	w.Header().Set(inertiaheader.HeaderContentType, contentTypeHTML)
-	w.WriteHeader(http.StatusOK)
 
-	data := TemplateData{T: renderCtx.T, InertiaHead: "", InertiaBody: ""}
+	if etag || timing != nil {
+		buf := bufPool.Get().(*bytes.Buffer) //nolint:forcetypeassert
+		defer func() {
+			buf.Reset()
+			bufPool.Put(buf)
+		}()
+
+		if err := r.renderHTML(ctx, buf, req, page, renderCtx); err != nil {
+			return err
+		}
+
+		timing.write(w)
+
+		if etag {
+			return writeWithETag(w, req, buf.Bytes())
+		}
+
+		w.WriteHeader(status)
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("inertia: failed to write response: %w", err)
+		}
+
+		return nil
+	}
+
+	w.WriteHeader(status)
+
+	return r.renderHTML(ctx, w, req, page, renderCtx)
+}
+
+// RenderPage resolves the page object for the component name without
+// writing a response.
+//
+// The page is passed through the configured page transformers.
+//
+// The req is used to read Inertia.js headers (e.g., partial reloads) and
+// the page URL, while ctx is used to resolve the props.
+//
+// It is useful to produce pages outside of an HTTP response, e.g.,
+// for emails, static prerendering or golden tests.
+func (r *Renderer) RenderPage(
+	ctx context.Context,
+	req *http.Request,
+	name string,
+	renderCtx RenderContext,
+) (page *Page, err error) {
+	debug.Assert(req != nil, "expected req to be defined")
+
+	ctx, done := r.startRender(ctx, req, name)
+	defer func() { done(page, err) }()
+
+	page, err = r.renderPage(ctx, req, name, renderCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkBudget(ctx, req, page, nil); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// renderPage is like RenderPage, but it doesn't call the render hooks.
+func (r *Renderer) renderPage(
+	ctx context.Context,
+	req *http.Request,
+	name string,
+	renderCtx RenderContext,
+) (*Page, error) {
+	renderCtx.Concurrency = cmp.Or(renderCtx.Concurrency, r.concurrency)
+	if renderCtx.Concurrency < 0 {
+		renderCtx.Concurrency = 0
+	}
+
+	req = req.WithContext(ctx)
+
+	page, err := r.newPage(req, name, renderCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transform := range r.transformers {
+		if err := transform(ctx, req, page); err != nil {
+			return nil, fmt.Errorf("inertia: failed to transform page: %w", err)
+		}
+	}
+
+	return page, nil
+}
+
+// encodePage encodes the page using the renderer encoder and checks
+// the encoded page against the configured budgets.
+//
+// The returned release function must be called once the bytes are
+// no longer used.
+func (r *Renderer) encodePage(ctx context.Context, req *http.Request, page *Page) ([]byte, func(), error) {
+	b, release, err := encodePage(r.encoder, page)
+	if err != nil {
+		return nil, nil, fmt.Errorf("inertia: failed to encode page: %w", err)
+	}
+
+	if err := r.checkBudget(ctx, req, page, b); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return b, release, nil
+}
+
+// RenderHTML renders the full HTML document of the component name into w.
+//
+// Unlike Render, it always renders HTML regardless of whether req is
+// an Inertia.js request, and it doesn't set any response headers.
+func (r *Renderer) RenderHTML(
+	ctx context.Context,
+	w io.Writer,
+	req *http.Request,
+	name string,
+	renderCtx RenderContext,
+) (err error) {
+	debug.Assert(req != nil, "expected req to be defined")
+
+	ctx, done := r.startRender(ctx, req, name)
+
+	var page *Page
+	defer func() { done(page, err) }()
+
+	page, err = r.renderPage(ctx, req, name, renderCtx)
+	if err != nil {
+		return err
+	}
+
+	return r.renderHTML(ctx, w, req, page, renderCtx)
+}
+
+// startRender calls the RenderStart hook and returns the context
+// to render with, along with a function to be called once the render
+// is done.
+func (r *Renderer) startRender(
+	ctx context.Context,
+	req *http.Request,
+	name string,
+) (context.Context, func(*Page, error)) {
+	if r.hooks == nil {
+		return ctx, func(*Page, error) {}
+	}
+
+	start := time.Now()
+	ctx = r.hooks.renderStart(ctx, RenderStartInfo{Request: req, Component: name})
+
+	return ctx, func(page *Page, err error) {
+		if err != nil {
+			page = nil
+		}
+
+		r.hooks.renderDone(ctx, RenderDoneInfo{
+			Request:   req,
+			Page:      page,
+			Err:       err,
+			Component: name,
+			Duration:  time.Since(start),
+		})
+	}
+}
+
+// renderHTML executes the root template for the given page into w.
+func (r *Renderer) renderHTML(
+	ctx context.Context,
+	w io.Writer,
+	req *http.Request,
+	page *Page,
+	renderCtx RenderContext,
+) error {
+	data := TemplateData{
+		T:           renderCtx.T,
+		Page:        page,
+		Request:     newTemplateRequest(req),
+		Nonce:       NonceFromContext(ctx),
+		InertiaHead: "",
+		InertiaBody: "",
+	}
 
 	if r.ssrClient != nil {
-		ssrData, err := r.ssrClient.Render(req.Context(), page)
+		if err := r.checkBudget(ctx, req, page, nil); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		ssrData, err := r.ssrClient.Render(ctx, page)
+		dur := time.Since(start)
+
+		serverTimingFromContext(ctx).recordSsr(dur)
+		r.hooks.ssrDone(ctx, SsrDoneInfo{
+			Err:       err,
+			Data:      ssrData,
+			Component: page.Component,
+			Duration:  dur,
+		})
+
 		if err != nil {
 			return fmt.Errorf("inertia: failed to render SSR data: %w", err)
 		}
@@ -191,69 +602,203 @@ func (r *Renderer) Render(w http.ResponseWriter, req *http.Request, name string,
 		data.InertiaHead = template.HTML(ssrData.Head) //nolint:gosec
 		data.InertiaBody = template.HTML(ssrData.Body) //nolint:gosec
 	} else {
-		body, err := r.makeRootView(page)
+		head, err := renderCtx.Head.HTML()
 		if err != nil {
-			return fmt.Errorf("inertia: failed to create an HTML container: %w", err)
+			return fmt.Errorf("inertia: failed to render head: %w", err)
 		}
 
+		pageBytes, release, err := r.encodePage(ctx, req, page)
+		if err != nil {
+			return err
+		}
+
+		body := r.makeRootView(pageBytes)
+		release()
+
+		data.InertiaHead = head
 		data.InertiaBody = body
 	}
 
-	if err := r.t.Execute(w, &data); err != nil {
+	var err error
+
+	start := time.Now()
+	name := r.rootTemplate(page.Component, renderCtx.RootTemplate)
+
+	if name != "" {
+		err = r.t.ExecuteTemplate(w, name, &data)
+	} else {
+		name = r.t.Name()
+		err = r.t.Execute(w, &data)
+	}
+
+	dur := time.Since(start)
+
+	serverTimingFromContext(ctx).recordTemplate(dur)
+	r.hooks.templateExecuted(ctx, TemplateExecutedInfo{
+		Err:       err,
+		Template:  name,
+		Component: page.Component,
+		Duration:  dur,
+	})
+
+	if err != nil {
 		return fmt.Errorf("inertia: failed to execute HTML template: %w", err)
 	}
 
 	return nil
 }
 
+// rootTemplate returns the name of the root template to render the
+// component with.
+//
+// It returns an empty string if the default template should be used.
+func (r *Renderer) rootTemplate(componentName, name string) string {
+	if name != "" {
+		return name
+	}
+
+	for _, rt := range r.rootTemplates {
+		if ok, _ := path.Match(rt.Pattern, componentName); ok {
+			return rt.Name
+		}
+	}
+
+	return ""
+}
+
 func (r *Renderer) newPage(req *http.Request, componentName string, renderCtx RenderContext) (*Page, error) {
 	rawProps := make([]Prop, 0, len(renderCtx.Props)+1)
 	rawProps = append(rawProps, renderCtx.Props...)
 	rawProps = append(rawProps, r.makeValidationErrors(renderCtx.ValidationErrorer, renderCtx.ErrorBag))
 
-	props, err := r.makeProps(req, componentName, rawProps, r.concurrency)
+	start := time.Now()
+
+	props, err := r.makeProps(req, componentName, rawProps, renderCtx.Concurrency)
 	if err != nil {
 		return nil, err
 	}
 
+	serverTimingFromContext(req.Context()).recordProps(time.Since(start))
+
 	deferredProps := r.makeDeferredProps(req, componentName, rawProps)
 	mergeProps := r.makeMergeProps(
 		rawProps,
 		extractHeaderValueList(req.Header.Get(inertiaheader.HeaderXInertiaReset)),
 	)
+	sensitiveProps := r.makeSensitiveProps(rawProps, props)
+
+	d("Resolved props of %s: %v", componentName, redactProps(props, sensitiveProps))
+
+	if ctx := req.Context(); r.logger.Enabled(ctx, slog.LevelDebug) {
+		r.logger.LogAttrs(ctx, slog.LevelDebug, "inertia: resolved props",
+			append(requestAttrs(req, componentName), slog.Any("props", slices.Sorted(maps.Keys(props))))...)
+	}
 
 	return &Page{
 		Component:      componentName,
 		Props:          props,
 		DeferredProps:  deferredProps,
 		MergeProps:     mergeProps,
-		URL:            req.RequestURI,
+		URL:            r.urlResolver(req),
 		Version:        r.version,
 		ClearHistory:   renderCtx.ClearHistory,
-		EncryptHistory: renderCtx.EncryptHistory,
+		EncryptHistory: renderCtx.EncryptHistory || hasSensitiveProps(rawProps),
+		Extensions:     makeExtensions(&renderCtx),
+		sensitiveProps: sensitiveProps,
 	}, nil
 }
 
-// makeRootView creates a root view element with the given page data.
-func (r *Renderer) makeRootView(page *Page) (template.HTML, error) {
+// makeExtensions creates the page extensions of the render context,
+// including the head, if any. An extension set with the HeadExtension key
+// takes precedence over the head.
+func makeExtensions(renderCtx *RenderContext) map[string]any {
+	extensions := maps.Clone(renderCtx.Extensions)
+	if renderCtx.Head.Empty() {
+		return extensions
+	}
+
+	if extensions == nil {
+		extensions = make(map[string]any, 1)
+	}
+
+	if _, ok := extensions[HeadExtension]; !ok {
+		head := renderCtx.Head
+		extensions[HeadExtension] = &head
+	}
+
+	return extensions
+}
+
+// makeSensitiveProps creates a list of sensitive props included in
+// the resolved props.
+func (r *Renderer) makeSensitiveProps(props []Prop, resolved map[string]any) []string {
+	var sensitiveProps []string
+
+	for _, p := range props {
+		if !p.sensitive {
+			continue
+		}
+
+		if _, ok := resolved[p.key]; ok {
+			sensitiveProps = append(sensitiveProps, p.key)
+		}
+	}
+
+	return sensitiveProps
+}
+
+// hasSensitiveProps reports whether any of the declared props is sensitive.
+//
+// The history encryption must not depend on the resolved props: the client
+// merges the props of partial reloads into the page, so a sensitive prop
+// loaded by an earlier reload is written to the history by later ones.
+func hasSensitiveProps(props []Prop) bool {
+	return slices.ContainsFunc(props, func(p Prop) bool { return p.sensitive })
+}
+
+// redactProps returns a copy of props with the values of sensitive
+// props redacted.
+func redactProps(props map[string]any, sensitiveProps []string) map[string]any {
+	if len(sensitiveProps) == 0 {
+		return props
+	}
+
+	m := maps.Clone(props)
+	for _, key := range sensitiveProps {
+		m[key] = redacted
+	}
+
+	return m
+}
+
+// makeRootView creates a root view element with the given encoded page.
+//
+// If the renderer is configured to use a script element, the page data is
+// embedded into a <script type="application/json"> element preceding
+// the root view element, otherwise into the data-page attribute.
+func (r *Renderer) makeRootView(pageBytes []byte) template.HTML {
 	var w strings.Builder
 
+	if r.useScriptElement {
+		_ = must.Must(w.WriteString(`<script data-page="`))
+		template.HTMLEscape(&w, []byte(r.rootViewID))
+		_ = must.Must(w.WriteString(`" type="application/json">`))
+		writeScriptJSON(&w, pageBytes)
+		_ = must.Must(w.WriteString(`</script>`))
+	}
+
 	_ = must.Must(w.WriteString(`<div id="`))
 	_ = must.Must(w.WriteString(r.rootViewID))
 	_ = must.Must(w.WriteRune('"'))
 	_ = must.Must(w.WriteRune(' '))
 
-	_ = must.Must(w.WriteString(`data-page="`))
-
-	pageBytes, err := json.Marshal(page)
-	if err != nil {
-		return "", fmt.Errorf("inertia: an error occurred while rendering page: %w", err)
+	if !r.useScriptElement {
+		_ = must.Must(w.WriteString(`data-page="`))
+		template.HTMLEscape(&w, pageBytes)
+		_ = must.Must(w.WriteRune('"'))
+		_ = must.Must(w.WriteRune(' '))
 	}
 
-	template.HTMLEscape(&w, pageBytes)
-	_ = must.Must(w.WriteRune('"'))
-	_ = must.Must(w.WriteRune(' '))
-
 	if r.rootViewAttrs != nil {
 		for _, kv := range r.rootViewAttrs {
 			// Skip the data-page attribute as it's already set.
@@ -273,7 +818,27 @@ func (r *Renderer) makeRootView(page *Page) (template.HTML, error) {
 	_ = must.Must(w.WriteString(`></div>`))
 
 	//nolint:gosec
-	return template.HTML(w.String()), nil
+	return template.HTML(w.String())
+}
+
+// writeScriptJSON writes JSON encoded b into w, so that it is safe to embed
+// into a <script> element.
+//
+// The '<' character can only appear inside JSON strings, so it is replaced
+// with its \u003c escape sequence, preventing "</script>" and "<!--" from
+// terminating the element early.
+func writeScriptJSON(w *strings.Builder, b []byte) {
+	for {
+		i := bytes.IndexByte(b, '<')
+		if i < 0 {
+			_ = must.Must(w.Write(b))
+			return
+		}
+
+		_ = must.Must(w.Write(b[:i]))
+		_ = must.Must(w.WriteString(`\u003c`))
+		b = b[i+1:]
+	}
 }
 
 func (r *Renderer) makeProps(
@@ -302,9 +867,9 @@ func (r *Renderer) makeProps(
 			continue
 		}
 
-		val, err := prop.value(ctx)
+		val, err := r.resolveProp(ctx, prop, false)
 		if err != nil {
-			return nil, fmt.Errorf("inertia: failed to resolve prop %s: %w", prop.key, err)
+			return nil, &PropError{Key: prop.key, Err: err}
 		}
 
 		m[prop.key] = val
@@ -335,9 +900,9 @@ func (r *Renderer) resolvePartialComponentRequest(
 		if prop.concurrent {
 			concurrentProps = append(concurrentProps, prop)
 		} else {
-			val, err := prop.value(ctx)
+			val, err := r.resolveProp(ctx, prop, false)
 			if err != nil {
-				return nil, fmt.Errorf("inertia: failed to resolve prop %s: %w", prop.key, err)
+				return nil, &PropError{Key: prop.key, Err: err}
 			}
 
 			m[key] = val
@@ -352,13 +917,9 @@ func (r *Renderer) resolvePartialComponentRequest(
 			group.SubmitErr(func() (pair[string, any], error) {
 				var kv pair[string, any]
 
-				val, err := prop.value(ctx)
+				val, err := r.resolveProp(ctx, prop, true)
 				if err != nil {
-					return kv, fmt.Errorf(
-						"inertia: failed to resolve prop %s: %w",
-						prop.key,
-						err,
-					)
+					return kv, &PropError{Key: prop.key, Err: err}
 				}
 
 				kv.key = prop.key
@@ -381,6 +942,28 @@ func (r *Renderer) resolvePartialComponentRequest(
 	return m, nil
 }
 
+// resolveProp resolves the value of the prop and reports it
+// to the PropResolved hook.
+func (r *Renderer) resolveProp(ctx context.Context, prop Prop, concurrent bool) (any, error) {
+	start := time.Now()
+	val, err := prop.value(ctx)
+	dur := time.Since(start)
+
+	serverTimingFromContext(ctx).recordProp(prop.key, dur)
+	r.hooks.propResolved(ctx, PropResolvedInfo{
+		Err:        err,
+		Key:        prop.key,
+		Duration:   dur,
+		Concurrent: concurrent,
+		Lazy:       prop.lazy,
+		Deferred:   prop.deferred,
+		Merge:      prop.mergeable,
+		Sensitive:  prop.sensitive,
+	})
+
+	return val, err
+}
+
 // makeDeferredProps creates a map of deferred props that should be resolved
 // on the client side.
 func (r *Renderer) makeDeferredProps(req *http.Request, componentName string, props []Prop) map[string][]string {
@@ -443,17 +1026,48 @@ func (r *Renderer) makeValidationErrors(errorers []ValidationErrorer, errorBag s
 
 // TemplateData represents the data that is passed to the HTML template.
 type TemplateData struct {
-	T           any
+	T any
+
+	// Page is the page being rendered.
+	Page *Page
+
+	// Request is a read-only view of the request the page is rendered for.
+	Request *TemplateRequest
+
+	// Nonce is the Content-Security-Policy nonce of the request, if any.
+	//
+	// See NonceMiddleware.
+	Nonce string
+
 	InertiaHead template.HTML
 	InertiaBody template.HTML
 }
 
+// Prop returns the value of the page prop by key, or nil if the prop is
+// not present, e.g., {{ .Prop "title" }}, or {{ $.Prop "title" }} within
+// a range or with block.
+func (d *TemplateData) Prop(key string) any {
+	if d.Page == nil {
+		return nil
+	}
+
+	return d.Page.Props[key]
+}
+
 // Location sends a redirect response to the client to guide to the
 // external URL.
 //
 // External URL is any URL that is not powered by Inertia.js.
+//
+// If url is not an allowed redirect target, the client is redirected to
+// the fallback URL instead, see SafeRedirectURL.
 func Location(w http.ResponseWriter, r *http.Request, url string) {
+	url = SafeRedirectURL(r, url)
+
 	if isInertiaRequest(r) {
+		LoggerFromRequest(r).LogAttrs(r.Context(), slog.LevelDebug, "inertia: external redirect",
+			slog.String("location", url))
+
 		h := w.Header()
 
 		h.Del(inertiaheader.HeaderVary)
@@ -464,12 +1078,15 @@ func Location(w http.ResponseWriter, r *http.Request, url string) {
 		return
 	}
 
-	inertiaredirect.Redirect(w, r, url)
+	inertiaredirect.Redirect(w, r, url, LoggerFromRequest(r))
 }
 
 // Redirect sends a redirect response to the client.
+//
+// If url is not an allowed redirect target, the client is redirected to
+// the fallback URL instead, see SafeRedirectURL.
 func Redirect(w http.ResponseWriter, r *http.Request, url string) {
-	inertiaredirect.Redirect(w, r, url)
+	inertiaredirect.Redirect(w, r, SafeRedirectURL(r, url), LoggerFromRequest(r))
 }
 
 // ErrorBagFromRequest extracts the Inertia.js error bag from the request,
@@ -1,11 +1,13 @@
 package inertia
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"html/template"
 	"net/http"
+	"strings"
 	"testing"
 	"testing/fstest"
 
@@ -691,6 +693,388 @@ func TestRenderer_Render(t *testing.T) {
 	}
 }
 
+func TestRenderer_RenderPage(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{Version: "1.0.0"})
+
+	t.Run("resolves page without response", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/about?tab=1", nil)
+
+		page, err := renderer.RenderPage(t.Context(), req, "About", NewRenderContext(
+			WithProps(Props{
+				NewProp("title", "About", nil),
+				NewOptional("lazy", LazyFunc(func(context.Context) (any, error) {
+					return "lazy", nil
+				})),
+			}),
+			WithEncryptHistory(),
+		))
+		require.NoError(t, err)
+
+		assert.Equal(t, "About", page.Component)
+		assert.Equal(t, "/about?tab=1", page.URL)
+		assert.Equal(t, "1.0.0", page.Version)
+		assert.True(t, page.EncryptHistory)
+		assert.Equal(t, "About", page.Props["title"])
+		assert.NotContains(t, page.Props, "lazy")
+	})
+
+	t.Run("uses context to resolve props", func(t *testing.T) {
+		t.Parallel()
+
+		type ctxKey struct{}
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "About",
+			Whitelist:        []string{"lazy"},
+		})
+		ctx := context.WithValue(t.Context(), ctxKey{}, "from-context")
+
+		page, err := renderer.RenderPage(ctx, req, "About", NewRenderContext(
+			WithProps(NewOptional("lazy", LazyFunc(func(ctx context.Context) (any, error) {
+				return ctx.Value(ctxKey{}), nil
+			}))),
+		))
+		require.NoError(t, err)
+
+		assert.Equal(t, "from-context", page.Props["lazy"])
+	})
+
+	t.Run("returns prop resolution error", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "About",
+		})
+
+		_, err := renderer.RenderPage(t.Context(), req, "About", NewRenderContext(
+			WithProps(NewOptional("lazy", LazyFunc(func(context.Context) (any, error) {
+				return nil, errors.New("boom")
+			}))),
+		))
+		require.Error(t, err)
+	})
+}
+
+func TestRenderer_RenderHTML(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{Version: "1.0.0"})
+
+	t.Run("renders html for non-inertia request", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		var buf bytes.Buffer
+
+		err := renderer.RenderHTML(t.Context(), &buf, req, "Home", NewRenderContext())
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "<title>Test Template</title>")
+		assert.Contains(t, buf.String(), `<div id="app" data-page="`)
+		assert.Contains(t, buf.String(), template.HTMLEscapeString(`"component":"Home"`))
+	})
+
+	t.Run("renders html for inertia request", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		var buf bytes.Buffer
+
+		err := renderer.RenderHTML(t.Context(), &buf, req, "Home", NewRenderContext())
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), `<div id="app" data-page="`)
+	})
+}
+
+func TestRenderer_RootTemplates(t *testing.T) {
+	t.Parallel()
+
+	tpl := template.Must(template.New("root").Parse(`root:{{ .InertiaBody }}`))
+	template.Must(tpl.New("admin").Parse(`admin:{{ .InertiaBody }}`))
+	template.Must(tpl.New("widget").Parse(`widget:{{ .InertiaBody }}`))
+
+	renderer := New(tpl, &Config{
+		RootTemplates: []RootTemplate{
+			{Pattern: "Admin/*", Name: "admin"},
+			{Pattern: "Widgets/*", Name: "widget"},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		component  string
+		options    []Option
+		wantPrefix string
+	}{
+		{name: "default template", component: "Home", wantPrefix: "root:"},
+		{name: "matching pattern", component: "Admin/Users", wantPrefix: "admin:"},
+		{name: "another matching pattern", component: "Widgets/Chat", wantPrefix: "widget:"},
+		{name: "pattern doesn't match nested", component: "Admin/Users/Edit", wantPrefix: "root:"},
+		{
+			name:       "render option overrides pattern",
+			component:  "Admin/Users",
+			options:    []Option{WithRootTemplate("widget")},
+			wantPrefix: "widget:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+			var buf bytes.Buffer
+
+			err := renderer.RenderHTML(t.Context(), &buf, req, tt.component, NewRenderContext(tt.options...))
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(buf.String(), tt.wantPrefix), "unexpected root template: %s", buf.String())
+		})
+	}
+
+	t.Run("unknown template", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			New(tpl, &Config{RootTemplates: []RootTemplate{{Pattern: "*", Name: "unknown"}}})
+		})
+	})
+}
+
+func TestRenderer_TemplateData(t *testing.T) {
+	t.Parallel()
+
+	tpl := template.Must(template.New("root").Parse(
+		`<title>{{ .Prop "title" }}</title>` +
+			`<meta name="description" content="{{ .Prop "description" }}">` +
+			`{{ with .Page }}<meta name="og:title" content="{{ $.Prop "title" }}">{{ end }}` +
+			`<body class="{{ .Page.Component }}" data-url="{{ .Page.URL }}" data-lang="{{ .Request.Header.Get "Accept-Language" }}"` +
+			` data-cookie="{{ .Request.Header.Get "Cookie" }}" data-auth="{{ .Request.Header.Get "Authorization" }}">` +
+			`{{ .InertiaBody }}</body>`,
+	))
+	renderer := New(tpl, nil)
+
+	req, _ := inertiatest.NewRequest(http.MethodGet, "/about", nil)
+	req.Header.Set("Accept-Language", "en")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("Authorization", "Bearer secret")
+
+	var buf bytes.Buffer
+
+	err := renderer.RenderHTML(t.Context(), &buf, req, "About", NewRenderContext(
+		WithProps(Props{
+			NewProp("title", "About us", nil),
+			NewProp("description", "<b>About</b>", nil),
+		}),
+	))
+	require.NoError(t, err)
+
+	body := buf.String()
+	assert.Contains(t, body, "<title>About us</title>")
+	assert.Contains(t, body, `content="&lt;b&gt;About&lt;/b&gt;"`)
+	assert.Contains(t, body, `class="About"`)
+	assert.Contains(t, body, `data-url="/about"`)
+	assert.Contains(t, body, `data-lang="en"`)
+	assert.Contains(t, body, `content="About us">`)
+	assert.Contains(t, body, `data-cookie="" data-auth=""`)
+	assert.NotContains(t, body, "secret")
+	assert.Equal(t, "session=secret", req.Header.Get("Cookie"), "the request must not be modified")
+}
+
+func TestRenderer_UseScriptElement(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{
+		UseScriptElement: true,
+		RootViewID:       "root",
+		RootViewAttrs:    map[string]string{"class": "container", "data-page": "should-be-skipped"},
+	})
+
+	req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+	var buf bytes.Buffer
+
+	err := renderer.RenderHTML(t.Context(), &buf, req, "Home", NewRenderContext(
+		WithProps(NewProp("html", "</script><script>alert(1)</script><!--", nil)),
+	))
+	require.NoError(t, err)
+
+	body := buf.String()
+	assert.Contains(t, body, `<script data-page="root" type="application/json">{`)
+	assert.Contains(t, body, `<div id="root" class="container" ></div>`)
+	assert.NotContains(t, body, "should-be-skipped")
+	assert.Equal(t, 1, strings.Count(body, "</script>"), "page data must not terminate the script element")
+
+	start := strings.Index(body, `type="application/json">`) + len(`type="application/json">`)
+	end := strings.Index(body, "</script>")
+
+	var page Page
+	require.NoError(t, json.Unmarshal([]byte(body[start:end]), &page))
+	assert.Equal(t, "Home", page.Component)
+	assert.Equal(t, "</script><script>alert(1)</script><!--", page.Props["html"])
+}
+
+func TestRenderer_PageTransformers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transforms page in order", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{
+			PageTransformers: []PageTransformer{
+				func(_ context.Context, _ *http.Request, page *Page) error {
+					page.Props["build"] = "abc123"
+					return nil
+				},
+				func(_ context.Context, r *http.Request, page *Page) error {
+					if r.Header.Get("X-Role") != "admin" {
+						delete(page.Props, "secret")
+					}
+
+					page.Props["build"] = page.Props["build"].(string) + "-final"
+
+					return nil
+				},
+			},
+		})
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+		require.NoError(t, renderer.Render(w, req, "Home", NewRenderContext(
+			WithProps(NewProp("secret", "s3cr3t", nil)),
+		)))
+
+		var page Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+		assert.Equal(t, "abc123-final", page.Props["build"])
+		assert.NotContains(t, page.Props, "secret")
+	})
+
+	t.Run("aborts on error", func(t *testing.T) {
+		t.Parallel()
+
+		errBudget := errors.New("budget exceeded")
+		renderer := New(testTpl, &Config{
+			PageTransformers: []PageTransformer{
+				func(context.Context, *http.Request, *Page) error { return errBudget },
+			},
+		})
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+		err := renderer.Render(w, req, "Home", NewRenderContext())
+
+		require.ErrorIs(t, err, errBudget)
+		assert.Empty(t, w.Body.String())
+	})
+}
+
+func TestRenderer_SensitiveProps(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, nil)
+	lazyKey := LazyFunc(func(context.Context) (any, error) { return "s3cr3t", nil })
+	props := WithProps(Props{
+		NewProp("title", "Settings", nil),
+		NewOptional("public", LazyFunc(func(context.Context) (any, error) { return "public", nil })),
+		NewDeferred("api_key", lazyKey, &DeferredOptions{Sensitive: true}),
+	})
+
+	t.Run("sensitive prop not included", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		page, err := renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(props))
+		require.NoError(t, err)
+
+		assert.True(t, page.EncryptHistory, "a declared sensitive prop must enforce the history encryption")
+		assert.Empty(t, page.sensitiveProps)
+	})
+
+	t.Run("partial reload excluding sensitive prop", func(t *testing.T) {
+		t.Parallel()
+
+		// The first partial reload loads the sensitive prop.
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Settings",
+			Whitelist:        []string{"api_key"},
+		})
+
+		page, err := renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(props))
+		require.NoError(t, err)
+		assert.True(t, page.EncryptHistory)
+
+		// The second one doesn't, but the client keeps it in the merged page.
+		req, _ = inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Settings",
+			Whitelist:        []string{"public"},
+		})
+
+		page, err = renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(props))
+		require.NoError(t, err)
+
+		assert.NotContains(t, page.Props, "api_key")
+		assert.True(t, page.EncryptHistory)
+	})
+
+	t.Run("no sensitive props", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		page, err := renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(
+			WithProps(NewProp("title", "Settings", nil)),
+		))
+		require.NoError(t, err)
+
+		assert.False(t, page.EncryptHistory)
+	})
+
+	t.Run("sensitive prop included", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{
+			Inertia:          true,
+			PartialComponent: "Settings",
+			Whitelist:        []string{"api_key"},
+		})
+
+		page, err := renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(props))
+		require.NoError(t, err)
+
+		assert.True(t, page.EncryptHistory)
+		assert.Equal(t, []string{"api_key"}, page.sensitiveProps)
+		assert.Equal(t, "s3cr3t", page.Props["api_key"])
+		assert.Equal(t, redacted, redactProps(page.Props, page.sensitiveProps)["api_key"])
+		assert.Equal(t, "s3cr3t", page.Props["api_key"], "redaction must not modify the page")
+	})
+
+	t.Run("sensitive regular prop", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		page, err := renderer.RenderPage(t.Context(), req, "Settings", NewRenderContext(
+			WithProps(NewProp("token", "t0k3n", &PropOptions{Sensitive: true})),
+		))
+		require.NoError(t, err)
+
+		assert.True(t, page.EncryptHistory)
+	})
+}
+
 func TestLocation(t *testing.T) {
 	t.Parallel()
 
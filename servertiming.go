@@ -0,0 +1,134 @@
+package inertia
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+// DefaultServerTimingThreshold is the default duration from which
+// a prop is reported individually in the Server-Timing header.
+const DefaultServerTimingThreshold = 5 * time.Millisecond
+
+// Server-Timing metric names.
+const (
+	serverTimingProps    = "props"
+	serverTimingProp     = "prop"
+	serverTimingSsr      = "ssr"
+	serverTimingTemplate = "template"
+)
+
+type serverTimingCtxKey struct{}
+
+//nolint:gochecknoglobals
+var kServerTimingCtxKey = serverTimingCtxKey{}
+
+// serverTiming collects the durations of a single render
+// to be reported in the Server-Timing header.
+type serverTiming struct {
+	slowProps []pair[string, time.Duration]
+	props     time.Duration
+	ssr       time.Duration
+	template  time.Duration
+	threshold time.Duration
+	mu        sync.Mutex
+}
+
+// withServerTiming returns a context carrying a new serverTiming.
+func withServerTiming(ctx context.Context, threshold time.Duration) (context.Context, *serverTiming) {
+	//nolint:exhaustruct
+	st := &serverTiming{threshold: threshold}
+	return context.WithValue(ctx, kServerTimingCtxKey, st), st
+}
+
+// serverTimingFromContext returns the serverTiming of the render, if any.
+func serverTimingFromContext(ctx context.Context) *serverTiming {
+	st, _ := ctx.Value(kServerTimingCtxKey).(*serverTiming)
+	return st
+}
+
+// recordProp records the resolution duration of the prop key,
+// if it took at least the threshold.
+func (st *serverTiming) recordProp(key string, dur time.Duration) {
+	if st == nil || dur < st.threshold {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.slowProps = append(st.slowProps, pair[string, time.Duration]{key, dur})
+}
+
+func (st *serverTiming) recordProps(dur time.Duration) {
+	if st != nil {
+		st.props += dur
+	}
+}
+
+func (st *serverTiming) recordSsr(dur time.Duration) {
+	if st != nil {
+		st.ssr += dur
+	}
+}
+
+func (st *serverTiming) recordTemplate(dur time.Duration) {
+	if st != nil {
+		st.template += dur
+	}
+}
+
+// write adds the Server-Timing header to w.
+func (st *serverTiming) write(w http.ResponseWriter) {
+	if st == nil {
+		return
+	}
+
+	w.Header().Add(inertiaheader.HeaderServerTiming, st.String())
+}
+
+// String returns the value of the Server-Timing header.
+func (st *serverTiming) String() string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var sb strings.Builder
+
+	writeServerTimingMetric(&sb, serverTimingProps, "", st.props)
+
+	for _, p := range st.slowProps {
+		writeServerTimingMetric(&sb, serverTimingProp, p.key, p.value)
+	}
+
+	if st.ssr > 0 {
+		writeServerTimingMetric(&sb, serverTimingSsr, "", st.ssr)
+	}
+
+	if st.template > 0 {
+		writeServerTimingMetric(&sb, serverTimingTemplate, "", st.template)
+	}
+
+	return sb.String()
+}
+
+// writeServerTimingMetric writes a single Server-Timing metric to sb.
+func writeServerTimingMetric(sb *strings.Builder, name, desc string, dur time.Duration) {
+	if sb.Len() > 0 {
+		sb.WriteString(", ")
+	}
+
+	sb.WriteString(name)
+
+	if desc != "" {
+		sb.WriteString(`;desc=`)
+		sb.WriteString(strconv.QuoteToASCII(desc))
+	}
+
+	sb.WriteString(";dur=")
+	sb.WriteString(strconv.FormatFloat(float64(dur.Microseconds())/1000, 'f', -1, 64))
+}
@@ -0,0 +1,115 @@
+package inertia
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestServerTiming_String(t *testing.T) {
+	t.Parallel()
+
+	//nolint:exhaustruct
+	st := &serverTiming{
+		props:    12500 * time.Microsecond,
+		template: 1 * time.Millisecond,
+		slowProps: []pair[string, time.Duration]{
+			{"users", 10 * time.Millisecond},
+			{`say "hi"`, 2 * time.Millisecond},
+		},
+	}
+
+	assert.Equal(t,
+		`props;dur=12.5, prop;desc="users";dur=10, prop;desc="say \"hi\"";dur=2, template;dur=1`,
+		st.String(),
+	)
+}
+
+func TestRenderer_ServerTiming(t *testing.T) {
+	t.Parallel()
+
+	props := Props{
+		NewProp("users", []string{"alice"}, nil),
+		NewProp("stats", 1, nil),
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, nil)
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+		assert.Empty(t, w.Header().Get(inertiaheader.HeaderServerTiming))
+	})
+
+	t.Run("inertia request", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{ServerTiming: true, ServerTimingThreshold: time.Nanosecond})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+		header := w.Header().Get(inertiaheader.HeaderServerTiming)
+		assert.Regexp(t, regexp.MustCompile(`^props;dur=[\d.]+, `), header)
+		assert.Contains(t, header, `prop;desc="users";dur=`)
+		assert.Contains(t, header, `prop;desc="stats";dur=`)
+		assert.NotContains(t, header, "template")
+	})
+
+	t.Run("fast props are not reported", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{ServerTiming: true, ServerTimingThreshold: time.Hour})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", &inertiatest.RequestConfig{Inertia: true})
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+		assert.Regexp(t, regexp.MustCompile(`^props;dur=[\d.]+$`),
+			w.Header().Get(inertiaheader.HeaderServerTiming))
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+
+		renderer := New(testTpl, &Config{ServerTiming: true})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `id="app"`)
+		assert.Regexp(t, regexp.MustCompile(`^props;dur=[\d.]+, template;dur=[\d.]+$`),
+			w.Header().Get(inertiaheader.HeaderServerTiming))
+	})
+
+	t.Run("html with ssr", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		client := NewMockSsrClient(ctrl)
+		client.EXPECT().Render(gomock.Any(), gomock.Any()).Return(&SsrTemplateData{
+			Head: "<title>Users</title>",
+			Body: `<div id="app"></div>`,
+		}, nil)
+
+		renderer := New(testTpl, &Config{ServerTiming: true, SsrClient: client})
+		req, w := inertiatest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, renderer.Render(w, req, "Users", NewRenderContext(WithProps(props))))
+
+		header := w.Header().Get(inertiaheader.HeaderServerTiming)
+		assert.Contains(t, header, "ssr;dur=")
+		assert.Contains(t, header, "template;dur=")
+	})
+}
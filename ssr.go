@@ -20,30 +20,53 @@ type SsrTemplateData struct {
 // SsrClient is a client that makes requests to a server-side rendering service.
 type SsrClient interface {
 	// Render makes a request to the server-side rendering service with the given page data.
+	//
+	// The Content-Security-Policy nonce of the request, if any, can be
+	// retrieved from the context using NonceFromContext. It must not be
+	// added to the page, as the page is embedded in the HTML response.
 	Render(context.Context, *Page) (*SsrTemplateData, error)
 }
 
 // ssr is an HTTP client that makes requests to a server-side rendering service.
 type ssr struct {
-	client *http.Client
-	url    string
+	client  *http.Client
+	encoder JSONEncoder
+	url     string
 }
 
 // NewHTTPSsrClient creates a new SsrClient that makes requests to the given HTTP client.
 // If client is nil, http.DefaultClient is used.
+//
+// The Content-Security-Policy nonce of the request, if any, is sent in
+// the X-Inertia-Nonce header.
 func NewHTTPSsrClient(url string, client *http.Client) SsrClient {
 	if client == nil {
 		client = http.DefaultClient
 	}
 
-	return &ssr{client, url}
+	return &ssr{client, DefaultJSONEncoder, url}
+}
+
+// withSsrJSONEncoder returns a copy of client using enc to encode pages,
+// if client is created by NewHTTPSsrClient. Otherwise, client is returned as is.
+func withSsrJSONEncoder(client SsrClient, enc JSONEncoder) SsrClient {
+	s, ok := client.(*ssr)
+	if !ok {
+		return client
+	}
+
+	c := *s
+	c.encoder = enc
+
+	return &c
 }
 
 func (s *ssr) Render(ctx context.Context, p *Page) (*SsrTemplateData, error) {
-	b, err := json.Marshal(p)
+	b, release, err := encodePage(s.encoder, p)
 	if err != nil {
 		return nil, fmt.Errorf("inertia: failed to marshal page: %w", err)
 	}
+	defer release()
 
 	r, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, bytes.NewReader(b))
 	if err != nil {
@@ -52,6 +75,10 @@ func (s *ssr) Render(ctx context.Context, p *Page) (*SsrTemplateData, error) {
 
 	r.Header.Set(inertiaheader.HeaderContentType, contentTypeJSON)
 
+	if nonce := NonceFromContext(ctx); nonce != "" {
+		r.Header.Set(inertiaheader.HeaderXInertiaNonce, nonce)
+	}
+
 	resp, err := s.client.Do(r)
 	if err != nil {
 		return nil, fmt.Errorf("inertia: failed to make HTTP request: %w", err)
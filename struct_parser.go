@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -24,6 +25,7 @@ var (
 	propOmitEmpty  = "omitempty"  //nolint:gochecknoglobals
 	propMergeable  = "mergeable"  //nolint:gochecknoglobals
 	propConcurrent = "concurrent" //nolint:gochecknoglobals
+	propSensitive  = "sensitive"  //nolint:gochecknoglobals
 )
 
 var lazyType = reflect.TypeOf((*Lazy)(nil)).Elem() //nolint:gochecknoglobals
@@ -53,6 +55,10 @@ var lazyType = reflect.TypeOf((*Lazy)(nil)).Elem() //nolint:gochecknoglobals
 //   - "omitempty": The field is omitted from the response if it is empty.
 //   - empty string: The field is not omitted from the response if it is empty.
 //
+// The "sensitive" option can be placed anywhere after the second item, e.g.,
+// "api_key,,sensitive". Sensitive fields enforce the history encryption
+// and are redacted in debug logs.
+//
 // By default, deferred (optional, deferred) fields are assigned to the
 // default group "default". An optional "inertiagroup" tag can be used for
 // grouping deferrable fields. If a non-deferrable field is tagged by "inertiagroup"
@@ -94,6 +100,7 @@ func ParseStruct(v any) (Props, error) {
 		fieldType := ""
 		mergeable := false
 		concurrent := false
+		sensitive := false
 
 		// If tag is not empty, parse it
 		if inertiaTag != "" {
@@ -123,6 +130,11 @@ func ParseStruct(v any) (Props, error) {
 				concurrent = true
 			}
 
+			// Sensitive flag can be placed at any position after the field type.
+			if len(parts) > 2 && slices.Contains(parts[2:], propSensitive) {
+				sensitive = true
+			}
+
 			// Skip empty fields if omitempty is presented.
 			if parts[len(parts)-1] == propOmitEmpty {
 				if fieldVal.IsZero() {
@@ -164,6 +176,7 @@ func ParseStruct(v any) (Props, error) {
 					Merge:      mergeable,
 					Group:      cmp.Or(inertiaGroup, DefaultDeferredGroup),
 					Concurrent: concurrent,
+					Sensitive:  sensitive,
 				},
 			)
 		case propTypeAlways:
@@ -172,12 +185,15 @@ func ParseStruct(v any) (Props, error) {
 			prop = NewProp(
 				fieldName,
 				fieldVal.Interface(),
-				&PropOptions{Merge: mergeable},
+				&PropOptions{Merge: mergeable, Sensitive: sensitive},
 			)
 		default:
 			return nil, fmt.Errorf("inertiaframe: unknown field type %q", fieldType)
 		}
 
+		// Optional and always props don't accept options.
+		prop.sensitive = sensitive
+
 		props = append(props, prop)
 	}
 
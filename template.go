@@ -0,0 +1,39 @@
+package inertia
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// TemplateRequest is a read-only view of the request passed to
+// the root template.
+type TemplateRequest struct {
+	// URL is a copy of the request URL.
+	URL *url.URL
+
+	// Header is a copy of the request headers, e.g., Accept-Language.
+	//
+	// Credentials, i.e., the Authorization, Cookie and Proxy-Authorization
+	// headers, are removed, as templates are not meant to handle them.
+	Header http.Header
+
+	Method string
+	Host   string
+}
+
+func newTemplateRequest(req *http.Request) *TemplateRequest {
+	u := *req.URL
+	u.User = nil
+
+	header := req.Header.Clone()
+	for _, key := range redactedHeaders {
+		header.Del(key)
+	}
+
+	return &TemplateRequest{
+		URL:    &u,
+		Header: header,
+		Method: req.Method,
+		Host:   req.Host,
+	}
+}
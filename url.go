@@ -0,0 +1,93 @@
+package inertia
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+)
+
+var (
+	_ URLResolver = DefaultURLResolver
+	_ URLResolver = ForwardedURLResolver
+)
+
+// URLResolver resolves the URL of the request as seen by the client.
+//
+// It is used to set the page URL and the URL to redirect to on
+// version mismatch.
+type URLResolver func(*http.Request) string
+
+// DefaultURLResolver resolves the URL from the original request URI.
+func DefaultURLResolver(req *http.Request) string {
+	if req.RequestURI != "" {
+		return req.RequestURI
+	}
+
+	return req.URL.RequestURI()
+}
+
+// ForwardedURLResolver resolves the URL from the original request URI
+// honoring the X-Forwarded-Prefix, X-Forwarded-Host and X-Forwarded-Proto
+// headers set by a reverse proxy.
+//
+// If X-Forwarded-Host is present, an absolute URL is returned, otherwise
+// the request URI prefixed with X-Forwarded-Prefix.
+//
+// Use it only if the application is behind a trusted proxy that
+// overrides the headers, otherwise clients can spoof the page URL.
+func ForwardedURLResolver(req *http.Request) string {
+	uri := DefaultURLResolver(req)
+
+	prefix := strings.TrimRight(firstHeaderValue(req, inertiaheader.HeaderXForwardedPrefix), "/")
+	if prefix != "" {
+		if !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+
+		uri = prefix + uri
+	}
+
+	host := firstHeaderValue(req, inertiaheader.HeaderXForwardedHost)
+	if host == "" {
+		return uri
+	}
+
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := strings.ToLower(firstHeaderValue(req, inertiaheader.HeaderXForwardedProto)); proto == "http" ||
+		proto == "https" {
+		scheme = proto
+	}
+
+	//nolint:exhaustruct
+	u := url.URL{Scheme: scheme, Host: host}
+
+	return u.String() + uri
+}
+
+// RequestURL returns the URL of the request as seen by the client,
+// resolved by the URLResolver of the Renderer attached by the Middleware.
+//
+// If there is no Renderer attached to the request, the request URI is returned.
+//
+// It is useful to redirect to the current page using Location when
+// the application is behind a reverse proxy.
+func RequestURL(req *http.Request) string {
+	renderer, ok := req.Context().Value(kCtxKey).(*Renderer)
+	if !ok {
+		return DefaultURLResolver(req)
+	}
+
+	return renderer.URL(req)
+}
+
+// firstHeaderValue returns the first value of a comma-separated header.
+func firstHeaderValue(req *http.Request, key string) string {
+	v, _, _ := strings.Cut(req.Header.Get(key), ",")
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,135 @@
+package inertia
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.inout.gg/inertia/internal/inertiaheader"
+	"go.inout.gg/inertia/internal/inertiatest"
+)
+
+func TestForwardedURLResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		tls      bool
+		expected string
+	}{
+		{name: "no headers", expected: "/users?page=2"},
+		{
+			name:     "prefix",
+			headers:  map[string]string{"X-Forwarded-Prefix": "/app/"},
+			expected: "/app/users?page=2",
+		},
+		{
+			name:     "prefix without leading slash",
+			headers:  map[string]string{"X-Forwarded-Prefix": "app"},
+			expected: "/app/users?page=2",
+		},
+		{
+			name:     "host",
+			headers:  map[string]string{"X-Forwarded-Host": "example.com"},
+			expected: "http://example.com/users?page=2",
+		},
+		{
+			name:     "host with tls",
+			headers:  map[string]string{"X-Forwarded-Host": "example.com"},
+			tls:      true,
+			expected: "https://example.com/users?page=2",
+		},
+		{
+			name: "host, proto and prefix",
+			headers: map[string]string{
+				"X-Forwarded-Host":   "example.com, proxy.internal",
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Prefix": "/app",
+			},
+			expected: "https://example.com/app/users?page=2",
+		},
+		{
+			name: "invalid proto",
+			headers: map[string]string{
+				"X-Forwarded-Host":  "example.com",
+				"X-Forwarded-Proto": "javascript",
+			},
+			expected: "http://example.com/users?page=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := inertiatest.NewRequest(http.MethodGet, "/users?page=2", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			assert.Equal(t, tt.expected, ForwardedURLResolver(req))
+		})
+	}
+}
+
+func TestRenderer_URLResolver(t *testing.T) {
+	t.Parallel()
+
+	renderer := New(testTpl, &Config{Version: "1.0.0", URLResolver: ForwardedURLResolver})
+
+	mux := http.NewServeMux()
+	mux.Handle("/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		MustRender(w, r, "Users", NewRenderContext())
+	}))
+
+	h := Middleware(renderer)(mux)
+
+	t.Run("page url", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{
+			Inertia: true,
+			Version: "1.0.0",
+		})
+		req.Header.Set(inertiaheader.HeaderXForwardedPrefix, "/app")
+
+		h.ServeHTTP(w, req)
+
+		var page Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "/app/users", page.URL)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		req, w := inertiatest.NewRequest(http.MethodGet, "/users", &inertiatest.RequestConfig{
+			Inertia: true,
+			Version: "0.9.0",
+		})
+		req.Header.Set(inertiaheader.HeaderXForwardedPrefix, "/app")
+
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "/app/users", w.Header().Get(inertiaheader.HeaderXInertiaLocation))
+	})
+}
+
+func TestRequestURL(t *testing.T) {
+	t.Parallel()
+
+	req, _ := inertiatest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(inertiaheader.HeaderXForwardedPrefix, "/app")
+
+	assert.Equal(t, "/users", RequestURL(req), "without renderer")
+}